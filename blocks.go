@@ -0,0 +1,164 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+// relationshipTarget authenticates the caller and resolves the user named in
+// the path of a block or mute request, responding with an error itself when
+// it returns false.
+func (cfg *apiConfig) relationshipTarget(w http.ResponseWriter, req *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return uuid.UUID{}, uuid.UUID{}, false
+	}
+
+	targetID, err := uuid.Parse(req.PathValue("userID"))
+	if err != nil {
+		log.Printf("failed to parse userID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid user ID")
+		return uuid.UUID{}, uuid.UUID{}, false
+	}
+
+	if targetID == userID {
+		respondWithError(w, 400, "You can't do that to yourself")
+		return uuid.UUID{}, uuid.UUID{}, false
+	}
+
+	return userID, targetID, true
+}
+
+func (cfg *apiConfig) handlerPostBlock(w http.ResponseWriter, req *http.Request) {
+	userID, blockedID, ok := cfg.relationshipTarget(w, req)
+	if !ok {
+		return
+	}
+
+	_, err := cfg.dbQueries.GetUserByID(req.Context(), blockedID)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to get user, Id not found: %s", err)
+		respondWithError(w, 404, "User not found")
+		return
+	default:
+		log.Printf("failed to get user: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(req.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	err = qtx.CreateBlock(req.Context(), database.CreateBlockParams{
+		BlockerID: userID,
+		BlockedID: blockedID,
+	})
+	if err != nil {
+		log.Printf("failed to create block: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	// Blocking ends follows in both directions, which also clears the
+	// blocked chirps out of both timelines.
+	err = qtx.DeleteFollowsBetween(req.Context(), database.DeleteFollowsBetweenParams{
+		FirstUserID:  userID,
+		SecondUserID: blockedID,
+	})
+	if err != nil {
+		log.Printf("failed to delete follows: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("failed to commit block: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerDeleteBlock(w http.ResponseWriter, req *http.Request) {
+	userID, blockedID, ok := cfg.relationshipTarget(w, req)
+	if !ok {
+		return
+	}
+
+	err := cfg.dbQueries.DeleteBlock(req.Context(), database.DeleteBlockParams{
+		BlockerID: userID,
+		BlockedID: blockedID,
+	})
+	if err != nil {
+		log.Printf("failed to delete block: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerPostMute(w http.ResponseWriter, req *http.Request) {
+	userID, mutedID, ok := cfg.relationshipTarget(w, req)
+	if !ok {
+		return
+	}
+
+	_, err := cfg.dbQueries.GetUserByID(req.Context(), mutedID)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to get user, Id not found: %s", err)
+		respondWithError(w, 404, "User not found")
+		return
+	default:
+		log.Printf("failed to get user: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	err = cfg.dbQueries.CreateMute(req.Context(), database.CreateMuteParams{
+		MuterID: userID,
+		MutedID: mutedID,
+	})
+	if err != nil {
+		log.Printf("failed to create mute: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerDeleteMute(w http.ResponseWriter, req *http.Request) {
+	userID, mutedID, ok := cfg.relationshipTarget(w, req)
+	if !ok {
+		return
+	}
+
+	err := cfg.dbQueries.DeleteMute(req.Context(), database.DeleteMuteParams{
+		MuterID: userID,
+		MutedID: mutedID,
+	})
+	if err != nil {
+		log.Printf("failed to delete mute: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(204)
+}
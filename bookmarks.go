@@ -0,0 +1,288 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/chirptext"
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+const maxBookmarkFolderNameLength = 25
+
+type bookmarkFolder struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func convertBookmarkFolder(dbFolder database.BookmarkFolder) bookmarkFolder {
+	return bookmarkFolder{
+		ID:        dbFolder.ID,
+		Name:      dbFolder.Name,
+		CreatedAt: dbFolder.CreatedAt,
+		UpdatedAt: dbFolder.UpdatedAt,
+	}
+}
+
+func (cfg *apiConfig) handlerPostBookmark(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		log.Printf("failed to parse chirpID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid chirp ID")
+		return
+	}
+
+	// The body is optional. Bookmarking again with a folder_id moves the
+	// bookmark, with "folder_id": null unfiles it, and without folder_id
+	// leaves it in its folder.
+	decoder := json.NewDecoder(req.Body)
+	params := map[string]json.RawMessage{}
+	err = decoder.Decode(&params)
+	if err != nil && err != io.EOF {
+		log.Printf("failed to decode parameters: %s", err)
+		respondWithError(w, 400, "Invalid request body")
+		return
+	}
+	var folderID uuid.NullUUID
+	rawFolderID, setFolder := params["folder_id"]
+	if setFolder {
+		err = json.Unmarshal(rawFolderID, &folderID)
+		if err != nil {
+			log.Printf("failed to decode folder_id: %s", err)
+			respondWithError(w, 400, "Invalid folder ID")
+			return
+		}
+	}
+
+	_, err = cfg.dbQueries.GetChirpByID(req.Context(), database.GetChirpByIDParams{
+		ID:       chirpID,
+		ViewerID: uuid.NullUUID{UUID: userID, Valid: true},
+	})
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to get chirp, Id not found: %s", err)
+		respondWithError(w, 404, "Chirp not found")
+		return
+	default:
+		log.Printf("failed to get chirp: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	if folderID.Valid {
+		_, err = cfg.dbQueries.GetBookmarkFolderByID(req.Context(), database.GetBookmarkFolderByIDParams{
+			ID:     folderID.UUID,
+			UserID: userID,
+		})
+		switch err {
+		case nil:
+		case sql.ErrNoRows:
+			log.Printf("failed to get bookmark folder, Id not found: %s", err)
+			respondWithError(w, 404, "Folder not found")
+			return
+		default:
+			log.Printf("failed to get bookmark folder: %s", err)
+			respondWithError(w, 500, "Internal server error")
+			return
+		}
+	}
+
+	err = cfg.dbQueries.CreateBookmark(req.Context(), database.CreateBookmarkParams{
+		UserID:    userID,
+		ChirpID:   chirpID,
+		FolderID:  folderID,
+		SetFolder: setFolder,
+	})
+	if err != nil {
+		log.Printf("failed to create bookmark: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerDeleteBookmark(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		log.Printf("failed to parse chirpID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid chirp ID")
+		return
+	}
+
+	err = cfg.dbQueries.DeleteBookmark(req.Context(), database.DeleteBookmarkParams{
+		UserID:  userID,
+		ChirpID: chirpID,
+	})
+	if err != nil {
+		log.Printf("failed to delete bookmark: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerGetBookmarks(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(req)
+	if err != nil {
+		log.Printf("failed to parse pagination: %s", err)
+		respondWithError(w, 400, "Invalid pagination")
+		return
+	}
+
+	params := database.GetBookmarkedChirpsParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	}
+	if folderIDString := req.URL.Query().Get("folder_id"); folderIDString != "" {
+		folderID, err := uuid.Parse(folderIDString)
+		if err != nil {
+			log.Printf("failed to parse folder ID string to uuid: %s", err)
+			respondWithError(w, 400, "Invalid folder ID")
+			return
+		}
+		params.FolderID = uuid.NullUUID{UUID: folderID, Valid: true}
+	}
+
+	chirps, err := cfg.dbQueries.GetBookmarkedChirps(req.Context(), params)
+	if err != nil {
+		log.Printf("failed to get bookmarks: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody, err := cfg.buildChirps(req.Context(), chirps, uuid.NullUUID{UUID: userID, Valid: true})
+	if err != nil {
+		log.Printf("failed to build chirps response: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
+
+func (cfg *apiConfig) handlerPostBookmarkFolders(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := struct {
+		Name string `json:"name"`
+	}{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		log.Printf("failed to decode parameters: %s", err)
+		respondWithError(w, 400, "Invalid request body")
+		return
+	}
+
+	name, err := chirptext.ValidateTextField(params.Name, maxBookmarkFolderNameLength, false)
+	switch {
+	case err == chirptext.ErrFieldTooLong:
+		respondWithError(w, 400, fmt.Sprintf("Name is too long, the limit is %d characters", maxBookmarkFolderNameLength))
+		return
+	case err != nil:
+		respondWithError(w, 400, "Name contains control characters")
+		return
+	case name == "":
+		respondWithError(w, 400, "Name can't be empty")
+		return
+	}
+
+	folder, err := cfg.dbQueries.CreateBookmarkFolder(req.Context(), database.CreateBookmarkFolderParams{
+		UserID: userID,
+		Name:   name,
+	})
+	if isUniqueViolation(err) {
+		log.Printf("failed to create bookmark folder: %s", err)
+		respondWithError(w, 409, "Folder already exists")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to create bookmark folder: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	respondWithJSON(w, 201, convertBookmarkFolder(folder))
+}
+
+func (cfg *apiConfig) handlerGetBookmarkFolders(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	folders, err := cfg.dbQueries.GetBookmarkFoldersByUserID(req.Context(), userID)
+	if err != nil {
+		log.Printf("failed to get bookmark folders: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := make([]bookmarkFolder, len(folders))
+	for i, folder := range folders {
+		respBody[i] = convertBookmarkFolder(folder)
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
+
+func (cfg *apiConfig) handlerDeleteBookmarkFolder(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	folderID, err := uuid.Parse(req.PathValue("folderID"))
+	if err != nil {
+		log.Printf("failed to parse folderID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid folder ID")
+		return
+	}
+
+	// The folder's bookmarks are kept and become unfiled.
+	deleted, err := cfg.dbQueries.DeleteBookmarkFolder(req.Context(), database.DeleteBookmarkFolderParams{
+		ID:     folderID,
+		UserID: userID,
+	})
+	if err != nil {
+		log.Printf("failed to delete bookmark folder: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, 404, "Folder not found")
+		return
+	}
+
+	w.WriteHeader(204)
+}
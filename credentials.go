@@ -0,0 +1,168 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/auth"
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+// reauthenticate checks the current password of the user making a
+// credential change, responding with an error itself when it returns false.
+func (cfg *apiConfig) reauthenticate(w http.ResponseWriter, req *http.Request, userID uuid.UUID, password string) bool {
+	currentUser, err := cfg.dbQueries.GetUserByID(req.Context(), userID)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to get user, Id not found: %s", err)
+		respondWithError(w, 401, "Unauthorized")
+		return false
+	default:
+		log.Printf("failed to get user: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return false
+	}
+
+	match, err := auth.CheckPasswordHash(password, currentUser.HashedPassword)
+	if !match || err != nil {
+		log.Printf("failed to check password: %v", err)
+		respondWithError(w, 403, "Incorrect password")
+		return false
+	}
+
+	return true
+}
+
+func (cfg *apiConfig) handlerPostUsersMePassword(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		log.Printf("failed to decode parameters: %s", err)
+		respondWithError(w, 400, "Invalid request body")
+		return
+	}
+
+	if params.NewPassword == "" {
+		respondWithError(w, 400, "New password is required")
+		return
+	}
+
+	if !cfg.reauthenticate(w, req, userID, params.CurrentPassword) {
+		return
+	}
+
+	hashedPassword, err := auth.HashPassword(params.NewPassword)
+	if err != nil {
+		log.Printf("failed to hash password: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(req.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	err = qtx.UpdateUserPassword(req.Context(), database.UpdateUserPasswordParams{
+		ID:             userID,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		log.Printf("failed to update password: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	// Every other session is signed out. The caller gets a fresh refresh
+	// token in place of the one that was just revoked.
+	err = qtx.RevokeUserRefreshTokens(req.Context(), userID)
+	if err != nil {
+		log.Printf("failed to revoke refresh tokens: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	token, refreshToken, err := cfg.createSession(req.Context(), qtx, userID)
+	if err != nil {
+		log.Printf("failed to create session: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("failed to commit password change: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	respondWithJSON(w, 200, struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}{
+		Token:        token,
+		RefreshToken: refreshToken,
+	})
+}
+
+func (cfg *apiConfig) handlerPostUsersMeEmail(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		log.Printf("failed to decode parameters: %s", err)
+		respondWithError(w, 400, "Invalid request body")
+		return
+	}
+
+	if params.Email == "" {
+		respondWithError(w, 400, "Email is required")
+		return
+	}
+
+	if !cfg.reauthenticate(w, req, userID, params.Password) {
+		return
+	}
+
+	updatedUser, err := cfg.dbQueries.UpdateUserEmail(req.Context(), database.UpdateUserEmailParams{
+		ID:    userID,
+		Email: params.Email,
+	})
+	if isUniqueViolation(err) {
+		log.Printf("failed to update email: %s", err)
+		respondWithError(w, 409, "Email already taken")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to update email: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	respondWithJSON(w, 200, buildUser(updatedUser))
+}
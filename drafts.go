@@ -0,0 +1,430 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	maxDraftAttempts = 5
+	draftRetryDelay  = time.Minute
+)
+
+type draft struct {
+	ID            uuid.UUID       `json:"id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	Body          string          `json:"body"`
+	QuotedChirpID *uuid.UUID      `json:"quoted_chirp_id,omitempty"`
+	MediaIDs      []uuid.UUID     `json:"media_ids"`
+	Poll          *pollParameters `json:"poll,omitempty"`
+	Visibility    string          `json:"visibility"`
+	ExpiresIn     *int32          `json:"expires_in,omitempty"`
+	PublishAt     *time.Time      `json:"publish_at,omitempty"`
+	FailedAt      *time.Time      `json:"failed_at,omitempty"`
+	FailureReason string          `json:"failure_reason,omitempty"`
+}
+
+type draftParameters struct {
+	chirpParameters
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+func convertDraft(dbDraft database.Draft) draft {
+	converted := draft{
+		ID:            dbDraft.ID,
+		CreatedAt:     dbDraft.CreatedAt,
+		UpdatedAt:     dbDraft.UpdatedAt,
+		Body:          dbDraft.Body,
+		MediaIDs:      dbDraft.MediaIds,
+		Visibility:    dbDraft.Visibility,
+		FailureReason: dbDraft.FailureReason.String,
+	}
+	if converted.MediaIDs == nil {
+		converted.MediaIDs = []uuid.UUID{}
+	}
+	if dbDraft.QuotedChirpID.Valid {
+		converted.QuotedChirpID = &dbDraft.QuotedChirpID.UUID
+	}
+	converted.Poll = draftChirpParameters(dbDraft).Poll
+	if dbDraft.ExpiresIn.Valid {
+		converted.ExpiresIn = &dbDraft.ExpiresIn.Int32
+	}
+	if dbDraft.PublishAt.Valid {
+		converted.PublishAt = &dbDraft.PublishAt.Time
+	}
+	if dbDraft.FailedAt.Valid {
+		converted.FailedAt = &dbDraft.FailedAt.Time
+	}
+	return converted
+}
+
+// draftChirpParameters rebuilds the chirp a draft describes.
+func draftChirpParameters(dbDraft database.Draft) chirpParameters {
+	params := chirpParameters{
+		Body:          dbDraft.Body,
+		QuotedChirpID: dbDraft.QuotedChirpID,
+		MediaIDs:      dbDraft.MediaIds,
+		Visibility:    dbDraft.Visibility,
+	}
+	if dbDraft.ExpiresIn.Valid {
+		params.ExpiresIn = &dbDraft.ExpiresIn.Int32
+	}
+	if dbDraft.PollClosesAt.Valid {
+		params.Poll = &pollParameters{
+			Options:  dbDraft.PollOptions,
+			ClosesAt: dbDraft.PollClosesAt.Time,
+		}
+	}
+	return params
+}
+
+// decodeDraftParameters reads a draft from the request body. Unscheduled drafts
+// may be incomplete, but a scheduled one must already be a valid chirp as of
+// its publish time.
+func decodeDraftParameters(req *http.Request) (draftParameters, error) {
+	params := draftParameters{}
+	decoder := json.NewDecoder(req.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		return draftParameters{}, invalidChirpError{"Invalid parameters"}
+	}
+
+	params.Visibility, err = normalizeVisibility(params.Visibility)
+	if err != nil {
+		return draftParameters{}, err
+	}
+
+	if params.PublishAt != nil {
+		if !params.PublishAt.After(time.Now()) {
+			return draftParameters{}, invalidChirpError{"publish_at must be in the future"}
+		}
+		err = params.validate(*params.PublishAt)
+		if err != nil {
+			return draftParameters{}, err
+		}
+	}
+
+	if params.MediaIDs == nil {
+		params.MediaIDs = []uuid.UUID{}
+	}
+	return params, nil
+}
+
+// columns returns the nullable draft columns shared by CreateDraft and
+// UpdateDraft.
+func (p draftParameters) columns() (pollOptions []string, pollClosesAt, publishAt sql.NullTime, expiresIn sql.NullInt32) {
+	pollOptions = []string{}
+	if p.Poll != nil {
+		if p.Poll.Options != nil {
+			pollOptions = p.Poll.Options
+		}
+		pollClosesAt = sql.NullTime{Time: p.Poll.ClosesAt.UTC(), Valid: true}
+	}
+	if p.PublishAt != nil {
+		publishAt = sql.NullTime{Time: p.PublishAt.UTC(), Valid: true}
+	}
+	if p.ExpiresIn != nil {
+		expiresIn = sql.NullInt32{Int32: *p.ExpiresIn, Valid: true}
+	}
+	return pollOptions, pollClosesAt, publishAt, expiresIn
+}
+
+// publishScheduledChirps publishes every draft whose publish_at has passed.
+func (cfg *apiConfig) publishScheduledChirps(ctx context.Context) error {
+	for ctx.Err() == nil {
+		published, err := cfg.publishNextScheduledChirp(ctx)
+		if err != nil {
+			return err
+		}
+		if !published {
+			return nil
+		}
+	}
+	return nil
+}
+
+// publishNextScheduledChirp claims one due draft with FOR UPDATE SKIP LOCKED
+// and deletes it in the same transaction that creates its chirp, so a draft is
+// published exactly once however many replicas run the scheduler. A draft that
+// no longer makes a valid chirp is marked as failed instead. Any other failure
+// is retried with backoff, up to maxDraftAttempts, so it doesn't block the
+// drafts due after it. It reports whether a draft was claimed.
+func (cfg *apiConfig) publishNextScheduledChirp(ctx context.Context) (bool, error) {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	scheduled, err := qtx.ClaimDueDraft(ctx)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim scheduled draft: %w", err)
+	}
+
+	// A rejected chirp may have aborted the transaction; rolling back to the
+	// savepoint keeps the draft locked while it is marked as failed.
+	_, err = tx.ExecContext(ctx, "SAVEPOINT publish_draft")
+	if err != nil {
+		return false, fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	_, err = cfg.createChirp(ctx, qtx, scheduled.UserID, draftChirpParameters(scheduled), time.Now())
+	var invalidErr invalidChirpError
+	var duplicateErr duplicateChirpError
+	switch {
+	case err == nil:
+		_, err = qtx.DeleteDraft(ctx, database.DeleteDraftParams{
+			ID:     scheduled.ID,
+			UserID: scheduled.UserID,
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to delete published draft: %w", err)
+		}
+	case errors.As(err, &invalidErr) || errors.Is(err, errRejectedContent) || errors.As(err, &duplicateErr):
+		reason := invalidErr.msg
+		switch {
+		case errors.Is(err, errRejectedContent):
+			reason = "Chirp contains prohibited content"
+		case errors.As(err, &duplicateErr):
+			reason = "Chirp was already posted"
+		}
+		log.Printf("failed to publish draft %s: %s", scheduled.ID, err)
+
+		_, err = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT publish_draft")
+		if err != nil {
+			return false, fmt.Errorf("failed to roll back to savepoint: %w", err)
+		}
+
+		err = qtx.MarkDraftFailed(ctx, database.MarkDraftFailedParams{
+			ID:            scheduled.ID,
+			FailureReason: sql.NullString{String: reason, Valid: true},
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to mark draft as failed: %w", err)
+		}
+	default:
+		log.Printf("failed to publish draft %s, attempt %d: %s", scheduled.ID, scheduled.Attempts+1, err)
+
+		_, err = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT publish_draft")
+		if err != nil {
+			return false, fmt.Errorf("failed to roll back to savepoint: %w", err)
+		}
+
+		if scheduled.Attempts+1 >= maxDraftAttempts {
+			err = qtx.MarkDraftFailed(ctx, database.MarkDraftFailedParams{
+				ID:            scheduled.ID,
+				FailureReason: sql.NullString{String: "Chirp could not be published", Valid: true},
+			})
+			if err != nil {
+				return false, fmt.Errorf("failed to mark draft as failed: %w", err)
+			}
+			break
+		}
+
+		err = qtx.RecordDraftAttempt(ctx, database.RecordDraftAttemptParams{
+			ID:             scheduled.ID,
+			RetryInSeconds: (draftRetryDelay << scheduled.Attempts).Seconds(),
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to record draft attempt: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, fmt.Errorf("failed to commit scheduled draft: %w", err)
+	}
+	return true, nil
+}
+
+func (cfg *apiConfig) handlerPostDrafts(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	params, err := decodeDraftParameters(req)
+	if err != nil {
+		log.Printf("failed to decode draft: %s", err)
+		respondWithError(w, 400, err.Error())
+		return
+	}
+
+	pollOptions, pollClosesAt, publishAt, expiresIn := params.columns()
+	createdDraft, err := cfg.dbQueries.CreateDraft(req.Context(), database.CreateDraftParams{
+		UserID:        userID,
+		Body:          params.Body,
+		QuotedChirpID: params.QuotedChirpID,
+		MediaIds:      params.MediaIDs,
+		PollOptions:   pollOptions,
+		PollClosesAt:  pollClosesAt,
+		PublishAt:     publishAt,
+		Visibility:    params.Visibility,
+		ExpiresIn:     expiresIn,
+	})
+	if err != nil {
+		log.Printf("failed to create draft: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	respondWithJSON(w, 201, convertDraft(createdDraft))
+}
+
+func (cfg *apiConfig) handlerGetDrafts(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(req)
+	if err != nil {
+		log.Printf("failed to parse pagination: %s", err)
+		respondWithError(w, 400, "Invalid pagination")
+		return
+	}
+
+	drafts, err := cfg.dbQueries.GetDraftsByUserID(req.Context(), database.GetDraftsByUserIDParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		log.Printf("failed to get drafts: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := make([]draft, len(drafts))
+	for i, currentDraft := range drafts {
+		respBody[i] = convertDraft(currentDraft)
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
+
+func (cfg *apiConfig) handlerGetDraftsByID(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	draftID, err := uuid.Parse(req.PathValue("draftID"))
+	if err != nil {
+		log.Printf("failed to parse draftID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid draft ID")
+		return
+	}
+
+	dbDraft, err := cfg.dbQueries.GetDraftByID(req.Context(), database.GetDraftByIDParams{
+		ID:     draftID,
+		UserID: userID,
+	})
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to get draft, Id not found: %s", err)
+		respondWithError(w, 404, "Draft not found")
+		return
+	default:
+		log.Printf("failed to get draft: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respondWithJSON(w, 200, convertDraft(dbDraft))
+}
+
+func (cfg *apiConfig) handlerPutDraftsByID(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	draftID, err := uuid.Parse(req.PathValue("draftID"))
+	if err != nil {
+		log.Printf("failed to parse draftID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid draft ID")
+		return
+	}
+
+	params, err := decodeDraftParameters(req)
+	if err != nil {
+		log.Printf("failed to decode draft: %s", err)
+		respondWithError(w, 400, err.Error())
+		return
+	}
+
+	// Updating waits for the scheduler if it holds the draft, and finds
+	// nothing once the draft has been published.
+	pollOptions, pollClosesAt, publishAt, expiresIn := params.columns()
+	updatedDraft, err := cfg.dbQueries.UpdateDraft(req.Context(), database.UpdateDraftParams{
+		ID:            draftID,
+		UserID:        userID,
+		Body:          params.Body,
+		QuotedChirpID: params.QuotedChirpID,
+		MediaIds:      params.MediaIDs,
+		PollOptions:   pollOptions,
+		PollClosesAt:  pollClosesAt,
+		PublishAt:     publishAt,
+		Visibility:    params.Visibility,
+		ExpiresIn:     expiresIn,
+	})
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to update draft, Id not found: %s", err)
+		respondWithError(w, 404, "Draft not found")
+		return
+	default:
+		log.Printf("failed to update draft: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	respondWithJSON(w, 200, convertDraft(updatedDraft))
+}
+
+func (cfg *apiConfig) handlerDeleteDraftsByID(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	draftID, err := uuid.Parse(req.PathValue("draftID"))
+	if err != nil {
+		log.Printf("failed to parse draftID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid draft ID")
+		return
+	}
+
+	deleted, err := cfg.dbQueries.DeleteDraft(req.Context(), database.DeleteDraftParams{
+		ID:     draftID,
+		UserID: userID,
+	})
+	if err != nil {
+		log.Printf("failed to delete draft: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, 404, "Draft not found")
+		return
+	}
+
+	w.WriteHeader(204)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+const expiredChirpBatchSize = 500
+
+// reapExpiredChirps deletes expired chirps in batches. Unlike deleted chirps
+// they can't be restored, so they are removed right away along with their
+// likes, hashtags, rechirps, polls and trending entries, and their media
+// blobs are deleted from the blob store.
+func (cfg *apiConfig) reapExpiredChirps(ctx context.Context) error {
+	total := int64(0)
+	for ctx.Err() == nil {
+		reaped, err := cfg.dbQueries.DeleteExpiredChirps(ctx, expiredChirpBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to delete expired chirps: %w", err)
+		}
+		total += reaped
+
+		if reaped < expiredChirpBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("reaped %d expired chirps", total)
+	}
+
+	return cfg.deleteUnreferencedBlobs(ctx)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/auth"
+	"github.com/LouisRemes-95/chirpy.git/internal/contentfilter"
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+type flaggedChirp struct {
+	Chirp     chirp     `json:"chirp"`
+	FlaggedAt time.Time `json:"flagged_at"`
+	Words     []string  `json:"words"`
+}
+
+// dbRuleSource loads content filter rules from the filter_rules table.
+type dbRuleSource struct {
+	q *database.Queries
+}
+
+func (s dbRuleSource) LoadRules(ctx context.Context) ([]contentfilter.Rule, error) {
+	dbRules, err := s.q.GetFilterRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]contentfilter.Rule, len(dbRules))
+	for i, dbRule := range dbRules {
+		action, err := contentfilter.ParseAction(dbRule.Action)
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = contentfilter.Rule{Word: dbRule.Word, Action: action}
+	}
+	return rules, nil
+}
+
+// newContentFilter builds the filter configured by CONTENT_FILTER_SOURCE:
+// "db" (the default) reads the filter_rules table and "file" reads the word
+// list at CONTENT_FILTER_FILE.
+func newContentFilter(ctx context.Context, q *database.Queries) (contentfilter.ContentFilter, error) {
+	var source contentfilter.RuleSource
+	switch os.Getenv("CONTENT_FILTER_SOURCE") {
+	case "", "db":
+		source = dbRuleSource{q: q}
+	case "file":
+		path := os.Getenv("CONTENT_FILTER_FILE")
+		if path == "" {
+			path = "content_filter.txt"
+		}
+		source = contentfilter.FileSource{Path: path}
+	default:
+		return nil, fmt.Errorf("unknown CONTENT_FILTER_SOURCE %q", os.Getenv("CONTENT_FILTER_SOURCE"))
+	}
+
+	return contentfilter.New(ctx, source)
+}
+
+// authorizeAdmin checks the ApiKey header against ADMIN_KEY. Admin endpoints
+// are disabled while no key is configured.
+func (cfg *apiConfig) authorizeAdmin(w http.ResponseWriter, req *http.Request) bool {
+	apiKey, err := auth.GetAPIKey(req.Header)
+	if err != nil {
+		log.Printf("failed to get api key: %v", err)
+		respondWithError(w, 401, "Unauthorized")
+		return false
+	}
+
+	if cfg.adminKey == "" || apiKey != cfg.adminKey {
+		respondWithError(w, 401, "Unauthorized")
+		return false
+	}
+	return true
+}
+
+func (cfg *apiConfig) handlerPostContentFilterReload(w http.ResponseWriter, req *http.Request) {
+	if !cfg.authorizeAdmin(w, req) {
+		return
+	}
+
+	err := cfg.contentFilter.Reload(req.Context())
+	if err != nil {
+		log.Printf("failed to reload content filter: %s", err)
+		respondWithError(w, 500, "Failed to reload content filter")
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerGetFlaggedChirps(w http.ResponseWriter, req *http.Request) {
+	if !cfg.authorizeAdmin(w, req) {
+		return
+	}
+
+	limit, offset, err := parsePagination(req)
+	if err != nil {
+		log.Printf("failed to parse pagination: %s", err)
+		respondWithError(w, 400, "Invalid pagination")
+		return
+	}
+
+	flagged, err := cfg.dbQueries.GetFlaggedChirps(req.Context(), database.GetFlaggedChirpsParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		log.Printf("failed to get flagged chirps: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	chirps := make([]database.Chirp, len(flagged))
+	for i, row := range flagged {
+		chirps[i] = database.Chirp{
+			ID:            row.ID,
+			CreatedAt:     row.CreatedAt,
+			UpdatedAt:     row.UpdatedAt,
+			Body:          row.Body,
+			UserID:        row.UserID,
+			LikeCount:     row.LikeCount,
+			RechirpOfID:   row.RechirpOfID,
+			QuotedChirpID: row.QuotedChirpID,
+			DeletedAt:     row.DeletedAt,
+			Visibility:    row.Visibility,
+			ExpiresAt:     row.ExpiresAt,
+			FannedOut:     row.FannedOut,
+		}
+	}
+
+	built, err := cfg.buildChirps(req.Context(), chirps, uuid.NullUUID{})
+	if err != nil {
+		log.Printf("failed to build chirps response: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := make([]flaggedChirp, len(flagged))
+	for i, row := range flagged {
+		respBody[i] = flaggedChirp{
+			Chirp:     built[i],
+			FlaggedAt: row.FlaggedAt,
+			Words:     row.Words,
+		}
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+// followPending is the status of a follow awaiting a private account's
+// approval.
+const followPending = "pending"
+
+type follow struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Handle    string    `json:"handle,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// canSeeFollows reports whether the viewer may list who a user follows and
+// is followed by. Lists of private accounts are only shown to the account
+// itself and its accepted followers.
+func (cfg *apiConfig) canSeeFollows(ctx context.Context, target database.User, viewerID uuid.NullUUID) (bool, error) {
+	if !target.IsPrivate {
+		return true, nil
+	}
+	if !viewerID.Valid {
+		return false, nil
+	}
+	if viewerID.UUID == target.ID {
+		return true, nil
+	}
+	return cfg.dbQueries.IsFollowing(ctx, database.IsFollowingParams{
+		FollowerID: viewerID.UUID,
+		FolloweeID: target.ID,
+	})
+}
+
+func (cfg *apiConfig) handlerPostFollow(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	followeeID, err := uuid.Parse(req.PathValue("userID"))
+	if err != nil {
+		log.Printf("failed to parse userID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid user ID")
+		return
+	}
+
+	if followeeID == userID {
+		respondWithError(w, 400, "You can't follow yourself")
+		return
+	}
+
+	// Following again is a no-op and reports the existing status, so an
+	// accepted follow isn't turned back into a pending request.
+	createdFollow, err := cfg.dbQueries.CreateFollow(req.Context(), database.CreateFollowParams{
+		FollowerID: userID,
+		FolloweeID: followeeID,
+	})
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to follow user, Id not found: %s", err)
+		respondWithError(w, 404, "User not found")
+		return
+	default:
+		log.Printf("failed to create follow: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	if createdFollow.Inserted {
+		notificationType := notificationFollow
+		if createdFollow.Status == followPending {
+			notificationType = notificationFollowRequest
+		}
+		err = notify(req.Context(), cfg.dbQueries, notificationType, followeeID, userID, uuid.NullUUID{})
+		if err != nil {
+			log.Printf("failed to notify followee: %s", err)
+		}
+	}
+
+	respondWithJSON(w, 200, struct {
+		Status string `json:"status"`
+	}{
+		Status: createdFollow.Status,
+	})
+}
+
+func (cfg *apiConfig) handlerDeleteFollow(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	followeeID, err := uuid.Parse(req.PathValue("userID"))
+	if err != nil {
+		log.Printf("failed to parse userID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid user ID")
+		return
+	}
+
+	// Unfollowing also withdraws a pending follow request.
+	err = cfg.dbQueries.DeleteFollow(req.Context(), database.DeleteFollowParams{
+		FollowerID: userID,
+		FolloweeID: followeeID,
+	})
+	if err != nil {
+		log.Printf("failed to delete follow: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerGetFollowers(w http.ResponseWriter, req *http.Request) {
+	target, limit, offset, ok := cfg.followListTarget(w, req)
+	if !ok {
+		return
+	}
+
+	followers, err := cfg.dbQueries.GetFollowers(req.Context(), database.GetFollowersParams{
+		UserID: target.ID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		log.Printf("failed to get followers: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := make([]follow, len(followers))
+	for i, follower := range followers {
+		respBody[i] = follow{
+			UserID:    follower.ID,
+			Handle:    follower.Handle.String,
+			CreatedAt: follower.CreatedAt,
+		}
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
+
+func (cfg *apiConfig) handlerGetFollowing(w http.ResponseWriter, req *http.Request) {
+	target, limit, offset, ok := cfg.followListTarget(w, req)
+	if !ok {
+		return
+	}
+
+	following, err := cfg.dbQueries.GetFollowing(req.Context(), database.GetFollowingParams{
+		UserID: target.ID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		log.Printf("failed to get following: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := make([]follow, len(following))
+	for i, followee := range following {
+		respBody[i] = follow{
+			UserID:    followee.ID,
+			Handle:    followee.Handle.String,
+			CreatedAt: followee.CreatedAt,
+		}
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
+
+// followListTarget resolves the user and page of a followers or following
+// request, responding with an error itself when it returns false.
+func (cfg *apiConfig) followListTarget(w http.ResponseWriter, req *http.Request) (database.User, int32, int32, bool) {
+	targetID, err := uuid.Parse(req.PathValue("userID"))
+	if err != nil {
+		log.Printf("failed to parse userID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid user ID")
+		return database.User{}, 0, 0, false
+	}
+
+	limit, offset, err := parsePagination(req)
+	if err != nil {
+		log.Printf("failed to parse pagination: %s", err)
+		respondWithError(w, 400, "Invalid pagination")
+		return database.User{}, 0, 0, false
+	}
+
+	target, err := cfg.dbQueries.GetUserByID(req.Context(), targetID)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to get user, Id not found: %s", err)
+		respondWithError(w, 404, "User not found")
+		return database.User{}, 0, 0, false
+	default:
+		log.Printf("failed to get user: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return database.User{}, 0, 0, false
+	}
+
+	allowed, err := cfg.canSeeFollows(req.Context(), target, cfg.viewerID(req))
+	if err != nil {
+		log.Printf("failed to check follow: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return database.User{}, 0, 0, false
+	}
+	if !allowed {
+		respondWithError(w, 403, "This account is private")
+		return database.User{}, 0, 0, false
+	}
+
+	return target, limit, offset, true
+}
+
+func (cfg *apiConfig) handlerGetFollowRequests(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(req)
+	if err != nil {
+		log.Printf("failed to parse pagination: %s", err)
+		respondWithError(w, 400, "Invalid pagination")
+		return
+	}
+
+	requests, err := cfg.dbQueries.GetFollowRequests(req.Context(), database.GetFollowRequestsParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		log.Printf("failed to get follow requests: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := make([]follow, len(requests))
+	for i, request := range requests {
+		respBody[i] = follow{
+			UserID:    request.ID,
+			Handle:    request.Handle.String,
+			CreatedAt: request.CreatedAt,
+		}
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
+
+func (cfg *apiConfig) handlerPostFollowRequestAccept(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	followerID, err := uuid.Parse(req.PathValue("userID"))
+	if err != nil {
+		log.Printf("failed to parse userID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid user ID")
+		return
+	}
+
+	accepted, err := cfg.dbQueries.AcceptFollowRequest(req.Context(), database.AcceptFollowRequestParams{
+		FollowerID: followerID,
+		FolloweeID: userID,
+	})
+	if err != nil {
+		log.Printf("failed to accept follow request: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	if accepted == 0 {
+		respondWithError(w, 404, "Follow request not found")
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerDeleteFollowRequest(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	followerID, err := uuid.Parse(req.PathValue("userID"))
+	if err != nil {
+		log.Printf("failed to parse userID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid user ID")
+		return
+	}
+
+	deleted, err := cfg.dbQueries.DeleteFollowRequest(req.Context(), database.DeleteFollowRequestParams{
+		FollowerID: followerID,
+		FolloweeID: userID,
+	})
+	if err != nil {
+		log.Printf("failed to delete follow request: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, 404, "Follow request not found")
+		return
+	}
+
+	w.WriteHeader(204)
+}
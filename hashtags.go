@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/chirptext"
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+)
+
+// saveHashtags indexes the hashtags of a newly created chirp.
+func saveHashtags(ctx context.Context, q *database.Queries, createdChirp database.Chirp) error {
+	for _, tag := range chirptext.Hashtags(createdChirp.Body) {
+		hashtag, err := q.UpsertHashtag(ctx, tag)
+		if err != nil {
+			return fmt.Errorf("failed to upsert hashtag %q: %w", tag, err)
+		}
+
+		err = q.CreateChirpHashtag(ctx, database.CreateChirpHashtagParams{
+			ChirpID:   createdChirp.ID,
+			HashtagID: hashtag.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to link hashtag %q: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+func (cfg *apiConfig) handlerGetHashtagChirps(w http.ResponseWriter, req *http.Request) {
+	tag := chirptext.NormalizeTag(req.PathValue("tag"))
+	if tag == "" {
+		respondWithError(w, 400, "Invalid hashtag")
+		return
+	}
+
+	limit, offset, err := parsePagination(req)
+	if err != nil {
+		log.Printf("failed to parse pagination: %s", err)
+		respondWithError(w, 400, "Invalid pagination")
+		return
+	}
+
+	newestFirst, err := parseSortOrder(req)
+	if err != nil {
+		log.Printf("failed to parse sort order: %s", err)
+		respondWithError(w, 400, "Invalid sort")
+		return
+	}
+
+	viewerID := cfg.viewerID(req)
+	chirps, err := cfg.dbQueries.GetChirpsByHashtag(req.Context(), database.GetChirpsByHashtagParams{
+		Tag:         tag,
+		ViewerID:    viewerID,
+		NewestFirst: newestFirst,
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		log.Printf("failed to get chirps by hashtag: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody, err := cfg.buildChirps(req.Context(), chirps, viewerID)
+	if err != nil {
+		log.Printf("failed to build chirps response: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
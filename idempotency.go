@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/auth"
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+)
+
+const (
+	idempotencyKeyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLength = 255
+	maxIdempotentBodySize   = 1 << 20
+)
+
+// responseRecorder buffers a response so that it can be stored before being
+// sent.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{header: http.Header{}, status: 200}
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	return r.body.Write(data)
+}
+
+func (r *responseRecorder) writeTo(w http.ResponseWriter) {
+	for key, values := range r.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(r.status)
+	_, err := w.Write(r.body.Bytes())
+	if err != nil {
+		log.Printf("failed to write response: %s", err)
+	}
+}
+
+// idempotent lets clients retry next safely by sending an Idempotency-Key
+// header. The first response for a key is stored for 24 hours and replayed to
+// later requests with the same key and body; reusing the key with another
+// body is rejected with a 422. Server errors aren't stored, so the request can
+// be retried with the same key. Keys are scoped to the endpoint and to the
+// caller, see idempotencyScope.
+func (cfg *apiConfig) idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		key := req.Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			next(w, req)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			respondWithError(w, 400, "Idempotency key is too long")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxIdempotentBodySize))
+		if err != nil {
+			log.Printf("failed to read request body: %s", err)
+			respondWithError(w, 413, "Request body is too large")
+			return
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := cfg.idempotencyScope(req)
+		fingerprint := sha256.Sum256(body)
+		claimed, err := cfg.dbQueries.ClaimIdempotencyKey(req.Context(), database.ClaimIdempotencyKeyParams{
+			Scope:       scope,
+			Key:         key,
+			Fingerprint: hex.EncodeToString(fingerprint[:]),
+		})
+		switch err {
+		case nil:
+		case sql.ErrNoRows:
+			cfg.replayIdempotentResponse(w, req, scope, key, hex.EncodeToString(fingerprint[:]))
+			return
+		default:
+			log.Printf("failed to claim idempotency key: %s", err)
+			respondWithError(w, 500, "Internal server error")
+			return
+		}
+
+		recorder := newResponseRecorder()
+		next(recorder, req)
+
+		// The client may have given up, but the response must still be stored
+		// or the key released.
+		ctx := context.WithoutCancel(req.Context())
+		if recorder.status >= 500 {
+			err = cfg.dbQueries.ReleaseIdempotencyKey(ctx, database.ReleaseIdempotencyKeyParams{
+				Scope: claimed.Scope,
+				Key:   claimed.Key,
+			})
+			if err != nil {
+				log.Printf("failed to release idempotency key: %s", err)
+			}
+		} else {
+			err = cfg.dbQueries.SaveIdempotentResponse(ctx, database.SaveIdempotentResponseParams{
+				Scope:               claimed.Scope,
+				Key:                 claimed.Key,
+				ResponseStatus:      sql.NullInt32{Int32: int32(recorder.status), Valid: true},
+				ResponseContentType: sql.NullString{String: recorder.header.Get("Content-Type"), Valid: true},
+				ResponseBody:        recorder.body.Bytes(),
+			})
+			if err != nil {
+				log.Printf("failed to save idempotent response: %s", err)
+			}
+		}
+
+		recorder.writeTo(w)
+	}
+}
+
+func (cfg *apiConfig) replayIdempotentResponse(w http.ResponseWriter, req *http.Request, scope, key, fingerprint string) {
+	stored, err := cfg.dbQueries.GetIdempotencyKey(req.Context(), database.GetIdempotencyKeyParams{
+		Scope: scope,
+		Key:   key,
+	})
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		// The first request failed and released the key in the meantime.
+		respondWithError(w, 409, "Request with this idempotency key failed, retry it")
+		return
+	default:
+		log.Printf("failed to get idempotency key: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	if stored.Fingerprint != fingerprint {
+		respondWithError(w, 422, "Idempotency key was already used with a different request")
+		return
+	}
+
+	if !stored.ResponseStatus.Valid {
+		respondWithError(w, 409, "Request with this idempotency key is still in progress")
+		return
+	}
+
+	if stored.ResponseContentType.String != "" {
+		w.Header().Set("Content-Type", stored.ResponseContentType.String)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(int(stored.ResponseStatus.Int32))
+	_, err = w.Write(stored.ResponseBody)
+	if err != nil {
+		log.Printf("failed to write response: %s", err)
+	}
+}
+
+// idempotencyScope scopes a key to the endpoint and to the caller. Callers
+// with an access token are identified by their user ID, so a retry sent after
+// refreshing the token still matches. Other callers are identified by the
+// Authorization header they sent, such as an API key.
+func (cfg *apiConfig) idempotencyScope(req *http.Request) string {
+	caller := req.Header.Get("Authorization")
+	if tokenString, err := auth.GetBearerToken(req.Header); err == nil {
+		if userID, err := auth.ValidateJWT(tokenString, cfg.secret); err == nil {
+			caller = "user " + userID.String()
+		}
+	}
+	credentials := sha256.Sum256([]byte(caller))
+	return fmt.Sprintf("%s %s %s", req.Method, req.URL.Path, hex.EncodeToString(credentials[:]))
+}
+
+// purgeIdempotencyKeys deletes the keys that can no longer be replayed.
+func (cfg *apiConfig) purgeIdempotencyKeys(ctx context.Context) error {
+	purged, err := cfg.dbQueries.DeleteExpiredIdempotencyKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	if purged > 0 {
+		log.Printf("purged %d idempotency keys", purged)
+	}
+	return nil
+}
@@ -1,8 +1,10 @@
 package auth
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -61,6 +63,35 @@ func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
 	return UserID, nil
 }
 
+// ErrUnauthorized is wrapped by the errors of Authenticate that mean the
+// request must be refused, as opposed to a failed lookup.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Authenticate returns the user a request's bearer token was issued to. A
+// valid token is refused when isActive reports that its user can no longer
+// act, such as a deleted account whose token has not expired yet.
+func Authenticate(ctx context.Context, headers http.Header, tokenSecret string, isActive func(context.Context, uuid.UUID) (bool, error)) (uuid.UUID, error) {
+	tokenString, err := GetBearerToken(headers)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+
+	userID, err := ValidateJWT(tokenString, tokenSecret)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+
+	active, err := isActive(ctx, userID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to check user %s: %w", userID, err)
+	}
+	if !active {
+		return uuid.Nil, fmt.Errorf("%w: user %s is no longer active", ErrUnauthorized, userID)
+	}
+
+	return userID, nil
+}
+
 func GetBearerToken(headers http.Header) (string, error) {
 	authorizationHeader := headers.Get(`Authorization`)
 	authorizationHeader = strings.TrimSpace(authorizationHeader)
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"testing"
 	"time"
@@ -126,3 +128,44 @@ func TestGetBearerTokenEmptyKey(t *testing.T) {
 		t.Errorf(`Token string GetBearerToken("Authorization", "Bearer ") = %v, but should fail since headers empty`, tokenString)
 	}
 }
+
+func TestAuthenticate(t *testing.T) {
+	tokenSecret := "testofsecretstring"
+	ID := uuid.New()
+	tokenString, err := MakeJWT(ID, tokenSecret, time.Minute)
+	if err != nil {
+		t.Fatalf(`failed to make JWT token string: %v`, err)
+	}
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+tokenString)
+
+	active := func(context.Context, uuid.UUID) (bool, error) { return true, nil }
+	deleted := func(context.Context, uuid.UUID) (bool, error) { return false, nil }
+	lookupErr := errors.New("connection refused")
+	failing := func(context.Context, uuid.UUID) (bool, error) { return false, lookupErr }
+
+	ReturnedID, err := Authenticate(context.Background(), headers, tokenSecret, active)
+	if err != nil || ReturnedID != ID {
+		t.Errorf(`Authenticate() = %v, %v, expected ID = %v`, ReturnedID, err, ID)
+	}
+
+	_, err = Authenticate(context.Background(), headers, tokenSecret, deleted)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf(`Authenticate() for a deleted user returned %v, expected ErrUnauthorized`, err)
+	}
+
+	_, err = Authenticate(context.Background(), http.Header{}, tokenSecret, active)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf(`Authenticate() without a token returned %v, expected ErrUnauthorized`, err)
+	}
+
+	_, err = Authenticate(context.Background(), headers, "wrongsecret", active)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf(`Authenticate() with a wrong secret returned %v, expected ErrUnauthorized`, err)
+	}
+
+	_, err = Authenticate(context.Background(), headers, tokenSecret, failing)
+	if !errors.Is(err, lookupErr) || errors.Is(err, ErrUnauthorized) {
+		t.Errorf(`Authenticate() with a failing lookup returned %v, expected the lookup error`, err)
+	}
+}
@@ -0,0 +1,24 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores opaque blobs under string keys. Keys are chosen by the
+// caller and may only contain characters that are safe in a URL path segment.
+type BlobStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Get(ctx context.Context, key string) (Blob, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Blob is the content of a stored blob. The caller must close Body.
+type Blob struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
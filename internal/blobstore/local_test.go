@@ -0,0 +1,53 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestLocalStoreRoundTrip(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf(`failed to create local store: %v`, err)
+	}
+
+	ctx := context.Background()
+	err = store.Put(ctx, "abc.jpg", "image/jpeg", []byte("jpeg bytes"))
+	if err != nil {
+		t.Fatalf(`failed to put blob: %v`, err)
+	}
+
+	blob, err := store.Get(ctx, "abc.jpg")
+	if err != nil {
+		t.Fatalf(`failed to get blob: %v`, err)
+	}
+	data, _ := io.ReadAll(blob.Body)
+	blob.Body.Close()
+	if string(data) != "jpeg bytes" || blob.ContentType != "image/jpeg" || blob.Size != 10 {
+		t.Errorf(`Get returned %q (%s, %d bytes), expected "jpeg bytes" (image/jpeg, 10 bytes)`, data, blob.ContentType, blob.Size)
+	}
+
+	err = store.Delete(ctx, "abc.jpg")
+	if err != nil {
+		t.Fatalf(`failed to delete blob: %v`, err)
+	}
+
+	_, err = store.Get(ctx, "abc.jpg")
+	if err != ErrNotFound {
+		t.Errorf(`Get after Delete returned %v, expected ErrNotFound`, err)
+	}
+}
+
+func TestLocalStoreRejectsPathKeys(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf(`failed to create local store: %v`, err)
+	}
+
+	for _, key := range []string{"", "../escape", "a/b", ".hidden"} {
+		if err := store.Put(context.Background(), key, "text/plain", nil); err == nil {
+			t.Errorf(`Put(%q) succeeded, expected an invalid key error`, key)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package chirptext
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// MaxLength is the longest a chirp body may be, as measured by Length.
+	MaxLength = 140
+	// URLWeight is the length every URL counts for, however long it is.
+	URLWeight = 23
+)
+
+var (
+	ErrEmptyBody        = errors.New("chirp body is empty")
+	ErrBodyTooLong      = errors.New("chirp body is too long")
+	ErrControlCharacter = errors.New("chirp body contains control characters")
+)
+
+var urlRegex = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"]+`)
+
+// Length returns the length of body as shown to users: the number of
+// grapheme clusters, so that an emoji made of several code points counts
+// once, with every URL counting for URLWeight.
+func Length(body string) int {
+	length := 0
+	last := 0
+	for _, loc := range findURLs(body) {
+		length += uniseg.GraphemeClusterCount(body[last:loc[0]]) + URLWeight
+		last = loc[1]
+	}
+	return length + uniseg.GraphemeClusterCount(body[last:])
+}
+
+// ValidateBody puts body in the form chirps are stored in, with "\r\n" line
+// breaks turned into "\n" and in Unicode NFC form, and checks that it is not
+// blank, has no control characters other than newlines and tabs, and is at
+// most MaxLength long.
+func ValidateBody(body string) (string, error) {
+	body = norm.NFC.String(strings.ReplaceAll(body, "\r\n", "\n"))
+
+	if strings.IndexFunc(body, isForbiddenControl) >= 0 {
+		return "", ErrControlCharacter
+	}
+	if strings.TrimFunc(body, isBlank) == "" {
+		return "", ErrEmptyBody
+	}
+	if Length(body) > MaxLength {
+		return "", ErrBodyTooLong
+	}
+	return body, nil
+}
+
+// findURLs returns the byte ranges of the URLs in body, leaving out trailing
+// punctuation that most likely ends the sentence rather than the URL.
+func findURLs(body string) [][]int {
+	locs := urlRegex.FindAllStringIndex(body, -1)
+	for _, loc := range locs {
+		loc[1] = loc[0] + len(strings.TrimRight(body[loc[0]:loc[1]], ".,;:!?)]}'"))
+	}
+	return locs
+}
+
+// isForbiddenControl reports whether r is a C0 or C1 control character, other
+// than a newline or a tab, or a bidirectional override that can make text
+// display differently from how it reads.
+func isForbiddenControl(r rune) bool {
+	switch {
+	case r == '\n' || r == '\t':
+		return false
+	case unicode.IsControl(r):
+		return true
+	case r >= '\u202A' && r <= '\u202E', r >= '\u2066' && r <= '\u2069':
+		return true
+	default:
+		return false
+	}
+}
+
+// isBlank reports whether r displays as nothing, including zero-width
+// characters that unicode.IsSpace doesn't cover.
+func isBlank(r rune) bool {
+	return unicode.IsSpace(r) || r == '\u200B' || r == '\u200C' || r == '\u200D' || r == '\u2060' || r == '\uFEFF'
+}
+
+// SameBody reports whether a and b are the same chirp body once case,
+// Unicode normalization and runs of whitespace are ignored.
+func SameBody(a, b string) bool {
+	return bodyKey(a) == bodyKey(b)
+}
+
+func bodyKey(body string) string {
+	return strings.Join(strings.Fields(cases.Fold().String(norm.NFC.String(body))), " ")
+}
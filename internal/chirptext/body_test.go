@@ -0,0 +1,72 @@
+package chirptext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLengthCountsGraphemeClusters(t *testing.T) {
+	cases := map[string]int{
+		"hello": 5,
+		"👍🏽":    1,
+		"👨\u200D👩\u200D👧\u200D👦 family": 8,
+		"🇫🇷":         1,
+		"Cafe\u0301": 4,
+		"see https://example.com/a/very/long/path?query=1": 4 + URLWeight,
+		"(http://go.dev).": 1 + URLWeight + 2,
+	}
+	for input, expected := range cases {
+		if got := Length(input); got != expected {
+			t.Errorf(`Length(%q) = %d, expected %d`, input, got, expected)
+		}
+	}
+}
+
+func TestValidateBody(t *testing.T) {
+	emoji := strings.Repeat("😀", 50)
+	body, err := ValidateBody(emoji)
+	if err != nil || body != emoji {
+		t.Errorf(`ValidateBody(50 emoji) = %q, %v, expected the body unchanged`, body, err)
+	}
+
+	body, err = ValidateBody("Cafe\u0301\r\nbar")
+	if err != nil || body != "Caf\u00e9\nbar" {
+		t.Errorf(`ValidateBody() = %q, %v, expected NFC form with "\n"`, body, err)
+	}
+
+	cases := map[string]error{
+		"":                       ErrEmptyBody,
+		"  \n\t ":                ErrEmptyBody,
+		"\u200B":                 ErrEmptyBody,
+		"bell\a":                 ErrControlCharacter,
+		"null\x00byte":           ErrControlCharacter,
+		"evil\u202Etxt.exe":      ErrControlCharacter,
+		strings.Repeat("a", 141): ErrBodyTooLong,
+		strings.Repeat("😀", 141): ErrBodyTooLong,
+		strings.Repeat("a", 140): nil,
+		strings.Repeat("é", 140): nil,
+		"ok\tthen":               nil,
+	}
+	for input, expected := range cases {
+		if _, err := ValidateBody(input); err != expected {
+			t.Errorf(`ValidateBody(%q) returned %v, expected %v`, input, err, expected)
+		}
+	}
+}
+
+func TestSameBody(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"hello world", "hello world", true},
+		{"Hello  World ", "hello world", true},
+		{"Café", "CAFÉ", true},
+		{"hello world", "hello world!", false},
+	}
+	for _, c := range cases {
+		if got := SameBody(c.a, c.b); got != c.expected {
+			t.Errorf(`SameBody(%q, %q) = %v, expected %v`, c.a, c.b, got, c.expected)
+		}
+	}
+}
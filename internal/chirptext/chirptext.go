@@ -0,0 +1,136 @@
+package chirptext
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	EntityHashtag = "hashtag"
+	EntityMention = "mention"
+)
+
+// Entity is a span of a chirp body with a special meaning, such as a hashtag
+// or a mention.
+// Start and End are byte offsets, RuneStart and RuneEnd are rune offsets; both
+// ranges are half-open.
+type Entity struct {
+	Type      string
+	Text      string
+	Value     string
+	Start     int
+	End       int
+	RuneStart int
+	RuneEnd   int
+}
+
+var (
+	hashtagRegex = regexp.MustCompile(`#[\p{L}\p{M}\p{N}_]+`)
+	tagRegex     = regexp.MustCompile(`^[\p{L}\p{M}\p{N}_]+$`)
+	mentionRegex = regexp.MustCompile(`@[A-Za-z0-9_]+`)
+	handleRegex  = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+)
+
+// NormalizeTag case folds a hashtag and puts it in Unicode NFC form, so that
+// "#Chirpy", "chirpy" and "#CHIRPY" all refer to the same tag. It returns an
+// empty string if s is not a valid tag.
+func NormalizeTag(s string) string {
+	s = strings.TrimPrefix(s, "#")
+	if !tagRegex.MatchString(s) || !strings.ContainsFunc(s, unicode.IsLetter) {
+		return ""
+	}
+	return norm.NFC.String(cases.Fold().String(s))
+}
+
+// ValidHandle reports whether handle can be used as a user handle: 1 to 15
+// ASCII letters, digits or underscores.
+func ValidHandle(handle string) bool {
+	return handleRegex.MatchString(handle)
+}
+
+// NormalizeHandle lowercases a handle, with or without its leading '@'. It
+// returns an empty string if s is not a valid handle.
+func NormalizeHandle(s string) string {
+	s = strings.TrimPrefix(s, "@")
+	if !ValidHandle(s) {
+		return ""
+	}
+	return strings.ToLower(s)
+}
+
+// ExtractHashtags returns the hashtags found in body, in order of appearance.
+// A '#' only starts a hashtag at the beginning of a word, and tags made only
+// of digits are ignored.
+func ExtractHashtags(body string) []Entity {
+	return extract(body, EntityHashtag, hashtagRegex, NormalizeTag)
+}
+
+// ExtractMentions returns the @handle mentions found in body, in order of
+// appearance. An '@' in the middle of a word, as in an email address, is not
+// a mention.
+func ExtractMentions(body string) []Entity {
+	return extract(body, EntityMention, mentionRegex, NormalizeHandle)
+}
+
+// Hashtags returns the distinct normalized hashtags found in body.
+func Hashtags(body string) []string {
+	return distinctValues(ExtractHashtags(body))
+}
+
+// Mentions returns the distinct normalized handles mentioned in body.
+func Mentions(body string) []string {
+	return distinctValues(ExtractMentions(body))
+}
+
+func extract(body, entityType string, re *regexp.Regexp, normalize func(string) string) []Entity {
+	var entities []Entity
+	for _, loc := range re.FindAllStringIndex(body, -1) {
+		start, end := loc[0], loc[1]
+		if start > 0 {
+			previous, _ := utf8.DecodeLastRuneInString(body[:start])
+			if isWordRune(previous) || strings.ContainsRune("#@&", previous) {
+				continue
+			}
+		}
+
+		text := body[start:end]
+		value := normalize(text)
+		if value == "" {
+			continue
+		}
+
+		runeStart := utf8.RuneCountInString(body[:start])
+		entities = append(entities, Entity{
+			Type:      entityType,
+			Text:      text,
+			Value:     value,
+			Start:     start,
+			End:       end,
+			RuneStart: runeStart,
+			RuneEnd:   runeStart + utf8.RuneCountInString(text),
+		})
+	}
+	return entities
+}
+
+func distinctValues(entities []Entity) []string {
+	var values []string
+	seen := map[string]bool{}
+	for _, entity := range entities {
+		if seen[entity.Value] {
+			continue
+		}
+		seen[entity.Value] = true
+		values = append(values, entity.Value)
+	}
+	return values
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
@@ -0,0 +1,90 @@
+package chirptext
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	cases := map[string]string{
+		"#Chirpy":       "chirpy",
+		"CHIRPY":        "chirpy",
+		"#Straße":       "strasse",
+		"#Cafe\u0301":   "caf\u00e9",
+		"#2024":         "",
+		"#bad-tag":      "",
+		"":              "",
+		"#golang_rocks": "golang_rocks",
+	}
+	for input, expected := range cases {
+		if got := NormalizeTag(input); got != expected {
+			t.Errorf(`NormalizeTag(%q) = %q, expected %q`, input, got, expected)
+		}
+	}
+}
+
+func TestExtractHashtagsOffsets(t *testing.T) {
+	body := "I ❤️ #Go and #gophers!"
+	entities := ExtractHashtags(body)
+	if len(entities) != 2 {
+		t.Fatalf(`ExtractHashtags(%q) returned %d entities, expected 2`, body, len(entities))
+	}
+
+	first := entities[0]
+	if body[first.Start:first.End] != "#Go" || first.Value != "go" {
+		t.Errorf(`first entity = %+v, expected "#Go" normalized to "go"`, first)
+	}
+	if first.RuneStart != 5 || first.RuneEnd != 8 {
+		t.Errorf(`first entity rune offsets = [%d, %d), expected [5, 8)`, first.RuneStart, first.RuneEnd)
+	}
+
+	second := entities[1]
+	if body[second.Start:second.End] != "#gophers" {
+		t.Errorf(`second entity text = %q, expected "#gophers"`, body[second.Start:second.End])
+	}
+}
+
+func TestExtractHashtagsIgnoresMidWord(t *testing.T) {
+	body := "issue#12 C# and a&#39;b ##double"
+	if entities := ExtractHashtags(body); len(entities) != 0 {
+		t.Errorf(`ExtractHashtags(%q) = %+v, expected no entities`, body, entities)
+	}
+}
+
+func TestHashtagsDistinct(t *testing.T) {
+	body := "#Chirpy #chirpy #CHIRPY #other"
+	tags := Hashtags(body)
+	if !slices.Equal(tags, []string{"chirpy", "other"}) {
+		t.Errorf(`Hashtags(%q) = %v, expected [chirpy other]`, body, tags)
+	}
+}
+
+func TestExtractMentions(t *testing.T) {
+	body := "hey @Alice, mail bob@example.com or ping @bob_99 and @this_handle_is_too_long"
+	entities := ExtractMentions(body)
+	if len(entities) != 2 {
+		t.Fatalf(`ExtractMentions(%q) returned %d entities, expected 2`, body, len(entities))
+	}
+	if entities[0].Text != "@Alice" || entities[0].Value != "alice" {
+		t.Errorf(`first mention = %+v, expected "@Alice" normalized to "alice"`, entities[0])
+	}
+	if body[entities[1].Start:entities[1].End] != "@bob_99" {
+		t.Errorf(`second mention text = %q, expected "@bob_99"`, body[entities[1].Start:entities[1].End])
+	}
+}
+
+func TestValidHandle(t *testing.T) {
+	cases := map[string]bool{
+		"alice":            true,
+		"Bob_99":           true,
+		"":                 false,
+		"with space":       false,
+		"ünicode":          false,
+		"sixteen_chars_xx": false,
+	}
+	for input, expected := range cases {
+		if got := ValidHandle(input); got != expected {
+			t.Errorf(`ValidHandle(%q) = %v, expected %v`, input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package chirptext
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+const (
+	MaxDisplayNameLength = 50
+	MaxBioLength         = 160
+	MaxLocationLength    = 30
+	MaxWebsiteLength     = 100
+)
+
+var ErrInvalidWebsite = errors.New("website is not an http or https URL")
+
+// ValidateWebsite checks that website is an absolute http or https URL of at
+// most MaxWebsiteLength characters. An empty website is valid.
+func ValidateWebsite(website string) (string, error) {
+	website = strings.TrimSpace(website)
+	if website == "" {
+		return "", nil
+	}
+	if len(website) > MaxWebsiteLength {
+		return "", ErrFieldTooLong
+	}
+
+	parsed, err := url.Parse(website)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", ErrInvalidWebsite
+	}
+	return parsed.String(), nil
+}
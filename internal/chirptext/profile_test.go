@@ -0,0 +1,24 @@
+package chirptext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateWebsite(t *testing.T) {
+	cases := map[string]error{
+		"":                                    nil,
+		"https://example.com":                 nil,
+		"http://example.com/about?me=1":       nil,
+		"example.com":                         ErrInvalidWebsite,
+		"javascript:alert(1)":                 ErrInvalidWebsite,
+		"ftp://example.com":                   ErrInvalidWebsite,
+		"https://":                            ErrInvalidWebsite,
+		"https://" + strings.Repeat("a", 100): ErrFieldTooLong,
+	}
+	for input, expected := range cases {
+		if _, err := ValidateWebsite(input); err != expected {
+			t.Errorf(`ValidateWebsite(%q) returned %v, expected %v`, input, err, expected)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package chirptext
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrFieldTooLong     = errors.New("text field is too long")
+	ErrInvalidCharacter = errors.New("text field contains control characters")
+)
+
+// ValidateTextField puts user-written text other than a chirp body, such as
+// a display name, a list name or a direct message, in NFC form with
+// surrounding whitespace trimmed, and checks that it is at most maxLength
+// grapheme clusters long and has no control characters. Only multiline fields
+// may contain newlines.
+func ValidateTextField(text string, maxLength int, multiline bool) (string, error) {
+	text = strings.TrimFunc(norm.NFC.String(strings.ReplaceAll(text, "\r\n", "\n")), isBlank)
+
+	for _, r := range text {
+		if isForbiddenControl(r) || (r == '\n' && !multiline) {
+			return "", ErrInvalidCharacter
+		}
+	}
+	if uniseg.GraphemeClusterCount(text) > maxLength {
+		return "", ErrFieldTooLong
+	}
+	return text, nil
+}
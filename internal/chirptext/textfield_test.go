@@ -0,0 +1,32 @@
+package chirptext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateTextField(t *testing.T) {
+	text, err := ValidateTextField("  Cafe\u0301 owner \n", MaxDisplayNameLength, false)
+	if err != nil || text != "Caf\u00e9 owner" {
+		t.Errorf(`ValidateTextField() = %q, %v, expected trimmed NFC text`, text, err)
+	}
+
+	cases := []struct {
+		input     string
+		multiline bool
+		expected  error
+	}{
+		{"", false, nil},
+		{"two\nlines", false, ErrInvalidCharacter},
+		{"two\r\nlines", true, nil},
+		{"bell\a", true, ErrInvalidCharacter},
+		{"evil\u202Etxt", false, ErrInvalidCharacter},
+		{strings.Repeat("😀", MaxDisplayNameLength), false, nil},
+		{strings.Repeat("😀", MaxDisplayNameLength+1), false, ErrFieldTooLong},
+	}
+	for _, c := range cases {
+		if _, err := ValidateTextField(c.input, MaxDisplayNameLength, c.multiline); err != c.expected {
+			t.Errorf(`ValidateTextField(%q, %v) returned %v, expected %v`, c.input, c.multiline, err, c.expected)
+		}
+	}
+}
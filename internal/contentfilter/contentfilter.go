@@ -0,0 +1,254 @@
+// Package contentfilter finds blocked words in chirp bodies.
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Action is what happens to a chirp containing a blocked word.
+type Action string
+
+const (
+	// ActionMask replaces the word with asterisks.
+	ActionMask Action = "mask"
+	// ActionReject refuses the whole chirp.
+	ActionReject Action = "reject"
+	// ActionFlag keeps the chirp as is but marks it for review.
+	ActionFlag Action = "flag"
+)
+
+// Mask replaces every masked word.
+const Mask = "****"
+
+// ParseAction returns the action named s.
+func ParseAction(s string) (Action, error) {
+	switch action := Action(strings.ToLower(strings.TrimSpace(s))); action {
+	case ActionMask, ActionReject, ActionFlag:
+		return action, nil
+	default:
+		return "", fmt.Errorf("unknown content filter action %q", s)
+	}
+}
+
+// Rule blocks a single word.
+type Rule struct {
+	Word   string
+	Action Action
+}
+
+// Match is a word of the filtered text that broke a rule. Start and End are
+// byte offsets in the original text.
+type Match struct {
+	Rule  Rule
+	Text  string
+	Start int
+	End   int
+}
+
+// Result is the outcome of filtering a text. Text has every masked word
+// replaced by Mask.
+type Result struct {
+	Text    string
+	Matches []Match
+}
+
+// Rejected reports whether a reject rule matched.
+func (r Result) Rejected() bool {
+	return r.has(ActionReject)
+}
+
+// Flagged reports whether a flag rule matched.
+func (r Result) Flagged() bool {
+	return r.has(ActionFlag)
+}
+
+// Words returns the distinct rule words that matched with the given action.
+func (r Result) Words(action Action) []string {
+	var words []string
+	for _, match := range r.Matches {
+		if match.Rule.Action == action && !contains(words, match.Rule.Word) {
+			words = append(words, match.Rule.Word)
+		}
+	}
+	return words
+}
+
+func (r Result) has(action Action) bool {
+	for _, match := range r.Matches {
+		if match.Rule.Action == action {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentFilter checks texts against a set of rules that can be reloaded
+// while the server runs.
+type ContentFilter interface {
+	Filter(text string) Result
+	Reload(ctx context.Context) error
+}
+
+// RuleSource loads the rules of a filter, for example from a file or a
+// database table.
+type RuleSource interface {
+	LoadRules(ctx context.Context) ([]Rule, error)
+}
+
+// WordFilter is a ContentFilter matching whole words. Words are compared after
+// case folding, stripping diacritics and undoing common leetspeak, so
+// "Kérfuffle!" and "k3rfuffl3" both match a rule for "kerfuffle".
+type WordFilter struct {
+	source RuleSource
+	rules  atomic.Pointer[map[string]Rule]
+}
+
+// New returns a WordFilter with the rules of source loaded.
+func New(ctx context.Context, source RuleSource) (*WordFilter, error) {
+	filter := &WordFilter{source: source}
+	err := filter.Reload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// Reload replaces the rules with the current ones from the source. Filtering
+// keeps using the previous rules until loading succeeds.
+func (f *WordFilter) Reload(ctx context.Context) error {
+	rules, err := f.source.LoadRules(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load content filter rules: %w", err)
+	}
+
+	byWord := make(map[string]Rule, len(rules))
+	for _, rule := range rules {
+		word := Normalize(rule.Word)
+		if word == "" {
+			continue
+		}
+		// When a word has several rules, the strictest one wins.
+		if existing, ok := byWord[word]; ok && severity(existing.Action) >= severity(rule.Action) {
+			continue
+		}
+		byWord[word] = rule
+	}
+
+	f.rules.Store(&byWord)
+	return nil
+}
+
+// Filter checks every word of text against the rules.
+func (f *WordFilter) Filter(text string) Result {
+	rules := *f.rules.Load()
+
+	var result Result
+	var masked strings.Builder
+	last := 0
+	for _, token := range tokenize(text) {
+		rule, ok := rules[Normalize(text[token.start:token.end])]
+		if !ok {
+			continue
+		}
+
+		result.Matches = append(result.Matches, Match{
+			Rule:  rule,
+			Text:  text[token.start:token.end],
+			Start: token.start,
+			End:   token.end,
+		})
+		if rule.Action == ActionMask {
+			masked.WriteString(text[last:token.start])
+			masked.WriteString(Mask)
+			last = token.end
+		}
+	}
+	masked.WriteString(text[last:])
+
+	result.Text = masked.String()
+	return result
+}
+
+var leetspeak = strings.NewReplacer(
+	"0", "o",
+	"1", "i",
+	"3", "e",
+	"4", "a",
+	"5", "s",
+	"7", "t",
+	"@", "a",
+	"$", "s",
+)
+
+// Normalize returns the form words are compared in: compatibility decomposed,
+// without diacritics, case folded and with leetspeak digits and symbols
+// replaced by the letters they stand for.
+func Normalize(word string) string {
+	word = norm.NFKD.String(word)
+	word = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, word)
+	return leetspeak.Replace(cases.Fold().String(word))
+}
+
+type token struct {
+	start int
+	end   int
+}
+
+// tokenize splits text into words made of letters, digits and marks. '@' and
+// '$' are kept inside words, as in "sh@rbert", and '$' may also start one; a
+// leading '@' is left out so that mentions are filtered like other words.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		inWord := isWordRune(r) || r == '$' || (r == '@' && start >= 0)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			tokens = append(tokens, token{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{start, len(text)})
+	}
+	return tokens
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r))
+}
+
+func severity(action Action) int {
+	switch action {
+	case ActionReject:
+		return 2
+	case ActionMask:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func contains(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
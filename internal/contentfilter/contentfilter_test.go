@@ -0,0 +1,124 @@
+package contentfilter
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type staticSource struct {
+	rules []Rule
+	err   error
+}
+
+func (s *staticSource) LoadRules(ctx context.Context) ([]Rule, error) {
+	return s.rules, s.err
+}
+
+func newTestFilter(t *testing.T, rules ...Rule) (*WordFilter, *staticSource) {
+	t.Helper()
+	source := &staticSource{rules: rules}
+	filter, err := New(context.Background(), source)
+	if err != nil {
+		t.Fatalf(`New() returned error: %v`, err)
+	}
+	return filter, source
+}
+
+func TestFilterMasksDespitePunctuationCaseAndLeetspeak(t *testing.T) {
+	filter, _ := newTestFilter(t,
+		Rule{Word: "kerfuffle", Action: ActionMask},
+		Rule{Word: "sharbert", Action: ActionMask},
+	)
+
+	cases := map[string]string{
+		"What a Kerfuffle!":           "What a ****!",
+		"sharbert, again":             "****, again",
+		"K3RFUFFL3 and sh@rbert":      "**** and ****",
+		"a kérfuffle":                 "a ****",
+		"kerfuffles are fine":         "kerfuffles are fine",
+		"#kerfuffle @sharbert":        "#**** @****",
+		"line\nkerfuffle\tsharbert.":  "line\n****\t****.",
+		"no bad words here, friends!": "no bad words here, friends!",
+	}
+	for input, expected := range cases {
+		result := filter.Filter(input)
+		if result.Text != expected {
+			t.Errorf(`Filter(%q).Text = %q, expected %q`, input, result.Text, expected)
+		}
+		if result.Rejected() || result.Flagged() {
+			t.Errorf(`Filter(%q) rejected or flagged a text with only mask rules`, input)
+		}
+	}
+}
+
+func TestFilterRejectAndFlag(t *testing.T) {
+	filter, _ := newTestFilter(t,
+		Rule{Word: "fornax", Action: ActionReject},
+		Rule{Word: "frobnicate", Action: ActionFlag},
+	)
+
+	result := filter.Filter("Fornax!")
+	if !result.Rejected() {
+		t.Errorf(`Filter("Fornax!") was not rejected`)
+	}
+
+	result = filter.Filter("please frobnicate this")
+	if !result.Flagged() || result.Rejected() {
+		t.Errorf(`Filter("please frobnicate this") = %+v, expected only flagged`, result)
+	}
+	if result.Text != "please frobnicate this" {
+		t.Errorf(`flagged text was changed to %q`, result.Text)
+	}
+	if words := result.Words(ActionFlag); !slices.Equal(words, []string{"frobnicate"}) {
+		t.Errorf(`Words(ActionFlag) = %v, expected [frobnicate]`, words)
+	}
+}
+
+func TestReloadKeepsRulesOnError(t *testing.T) {
+	filter, source := newTestFilter(t, Rule{Word: "kerfuffle", Action: ActionMask})
+
+	source.rules = []Rule{{Word: "sharbert", Action: ActionMask}}
+	if err := filter.Reload(context.Background()); err != nil {
+		t.Fatalf(`Reload() returned error: %v`, err)
+	}
+	if got := filter.Filter("kerfuffle sharbert").Text; got != "kerfuffle ****" {
+		t.Errorf(`after reload, Filter() = %q, expected "kerfuffle ****"`, got)
+	}
+
+	source.err = errors.New("source unavailable")
+	if err := filter.Reload(context.Background()); err == nil {
+		t.Fatalf(`Reload() with a failing source returned no error`)
+	}
+	if got := filter.Filter("sharbert").Text; got != "****" {
+		t.Errorf(`after failed reload, Filter() = %q, expected "****"`, got)
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	input := `
+# comment
+kerfuffle
+fornax   reject
+frobnicate FLAG
+`
+	rules, err := ParseRules(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf(`ParseRules() returned error: %v`, err)
+	}
+
+	expected := []Rule{
+		{Word: "kerfuffle", Action: ActionMask},
+		{Word: "fornax", Action: ActionReject},
+		{Word: "frobnicate", Action: ActionFlag},
+	}
+	if !slices.Equal(rules, expected) {
+		t.Errorf(`ParseRules() = %+v, expected %+v`, rules, expected)
+	}
+
+	if _, err := ParseRules(strings.NewReader("fornax explode")); err == nil {
+		t.Errorf(`ParseRules() accepted an unknown action`)
+	}
+}
@@ -0,0 +1,62 @@
+package contentfilter
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// FileSource loads rules from a word list file. Each line holds a word,
+// optionally followed by an action; words without one are masked. Blank
+// lines and lines starting with '#' are ignored:
+//
+//	# word     action
+//	kerfuffle
+//	sharbert   mask
+//	fornax     reject
+//	frobnicate flag
+type FileSource struct {
+	Path string
+}
+
+func (s FileSource) LoadRules(ctx context.Context) ([]Rule, error) {
+	file, err := os.Open(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseRules(file)
+}
+
+// ParseRules reads rules in the FileSource format.
+func ParseRules(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: expected a word and an optional action", line)
+		}
+
+		rule := Rule{Word: fields[0], Action: ActionMask}
+		if len(fields) == 2 {
+			action, err := ParseAction(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			rule.Action = action
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
@@ -7,29 +7,42 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 const createChirp = `-- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, quoted_chirp_id, visibility, expires_at)
 VALUES (
     gen_random_uuid(),
     NOW(),
     Now(),
     $1,
-    $2
+    $2,
+    $3,
+    $4,
+    $5
 )
-RETURNING id, created_at, updated_at, body, user_id
+RETURNING id, created_at, updated_at, body, user_id, like_count, rechirp_of_id, quoted_chirp_id, deleted_at, visibility, expires_at, fanned_out
 `
 
 type CreateChirpParams struct {
-	Body   string
-	UserID uuid.UUID
+	Body          string
+	UserID        uuid.UUID
+	QuotedChirpID uuid.NullUUID
+	Visibility    string
+	ExpiresAt     sql.NullTime
 }
 
 func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, createChirp, arg.Body, arg.UserID)
+	row := q.db.QueryRowContext(ctx, createChirp,
+		arg.Body,
+		arg.UserID,
+		arg.QuotedChirpID,
+		arg.Visibility,
+		arg.ExpiresAt,
+	)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -37,6 +50,13 @@ func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.LikeCount,
+		&i.RechirpOfID,
+		&i.QuotedChirpID,
+		&i.DeletedAt,
+		&i.Visibility,
+		&i.ExpiresAt,
+		&i.FannedOut,
 	)
 	return i, err
 }
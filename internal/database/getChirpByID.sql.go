@@ -12,12 +12,17 @@ import (
 )
 
 const getChirpByID = `-- name: GetChirpByID :one
-SELECT id, created_at, updated_at, body, user_id FROM chirps
-WHERE id = $1
+SELECT id, created_at, updated_at, body, user_id, like_count, rechirp_of_id, quoted_chirp_id, deleted_at, visibility, expires_at, fanned_out FROM chirps
+WHERE id = $1 AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW()) AND chirp_visible_to(user_id, visibility, $2::uuid)
 `
 
-func (q *Queries) GetChirpByID(ctx context.Context, id uuid.UUID) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, getChirpByID, id)
+type GetChirpByIDParams struct {
+	ID       uuid.UUID
+	ViewerID uuid.NullUUID
+}
+
+func (q *Queries) GetChirpByID(ctx context.Context, arg GetChirpByIDParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirpByID, arg.ID, arg.ViewerID)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -25,6 +30,13 @@ func (q *Queries) GetChirpByID(ctx context.Context, id uuid.UUID) (Chirp, error)
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.LikeCount,
+		&i.RechirpOfID,
+		&i.QuotedChirpID,
+		&i.DeletedAt,
+		&i.Visibility,
+		&i.ExpiresAt,
+		&i.FannedOut,
 	)
 	return i, err
 }
@@ -7,15 +7,34 @@ package database
 
 import (
 	"context"
+
+	"github.com/google/uuid"
 )
 
 const getChirps = `-- name: GetChirps :many
-SELECT id, created_at, updated_at, body, user_id FROM chirps
-ORDER BY created_at ASC
+SELECT id, created_at, updated_at, body, user_id, like_count, rechirp_of_id, quoted_chirp_id, deleted_at, visibility, expires_at, fanned_out FROM chirps
+WHERE deleted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW()) AND chirp_listed_for(user_id, visibility, $1::uuid) AND rechirp_listed_for(rechirp_of_id, $1::uuid)
+ORDER BY
+    CASE WHEN $2::boolean THEN created_at END DESC,
+    CASE WHEN NOT $2::boolean THEN created_at END ASC,
+    id
+LIMIT $3 OFFSET $4
 `
 
-func (q *Queries) GetChirps(ctx context.Context) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, getChirps)
+type GetChirpsParams struct {
+	ViewerID    uuid.NullUUID
+	NewestFirst bool
+	Limit       int32
+	Offset      int32
+}
+
+func (q *Queries) GetChirps(ctx context.Context, arg GetChirpsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirps,
+		arg.ViewerID,
+		arg.NewestFirst,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -29,6 +48,13 @@ func (q *Queries) GetChirps(ctx context.Context) ([]Chirp, error) {
 			&i.UpdatedAt,
 			&i.Body,
 			&i.UserID,
+			&i.LikeCount,
+			&i.RechirpOfID,
+			&i.QuotedChirpID,
+			&i.DeletedAt,
+			&i.Visibility,
+			&i.ExpiresAt,
+			&i.FannedOut,
 		); err != nil {
 			return nil, err
 		}
@@ -12,13 +12,32 @@ import (
 )
 
 const getChirpsByAuthorID = `-- name: GetChirpsByAuthorID :many
-SELECT id, created_at, updated_at, body, user_id FROM chirps
-WHERE user_id = $1
-ORDER BY created_at ASC
+SELECT id, created_at, updated_at, body, user_id, like_count, rechirp_of_id, quoted_chirp_id, deleted_at, visibility, expires_at, fanned_out FROM chirps
+WHERE user_id = $1 AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW()) AND chirp_listed_for(user_id, visibility, $2::uuid) AND rechirp_listed_for(rechirp_of_id, $2::uuid)
+AND NOT EXISTS (SELECT 1 FROM pinned_chirps WHERE pinned_chirps.chirp_id = chirps.id)
+ORDER BY
+    CASE WHEN $3::boolean THEN created_at END DESC,
+    CASE WHEN NOT $3::boolean THEN created_at END ASC,
+    id
+LIMIT $4 OFFSET $5
 `
 
-func (q *Queries) GetChirpsByAuthorID(ctx context.Context, userID uuid.UUID) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, getChirpsByAuthorID, userID)
+type GetChirpsByAuthorIDParams struct {
+	UserID      uuid.UUID
+	ViewerID    uuid.NullUUID
+	NewestFirst bool
+	Limit       int32
+	Offset      int32
+}
+
+func (q *Queries) GetChirpsByAuthorID(ctx context.Context, arg GetChirpsByAuthorIDParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsByAuthorID,
+		arg.UserID,
+		arg.ViewerID,
+		arg.NewestFirst,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -32,6 +51,13 @@ func (q *Queries) GetChirpsByAuthorID(ctx context.Context, userID uuid.UUID) ([]
 			&i.UpdatedAt,
 			&i.Body,
 			&i.UserID,
+			&i.LikeCount,
+			&i.RechirpOfID,
+			&i.QuotedChirpID,
+			&i.DeletedAt,
+			&i.Visibility,
+			&i.ExpiresAt,
+			&i.FannedOut,
 		); err != nil {
 			return nil, err
 		}
@@ -11,12 +11,233 @@ import (
 	"github.com/google/uuid"
 )
 
-type Chirp struct {
+type BlobDeletion struct {
+	BlobKey   string
+	CreatedAt time.Time
+}
+
+type Block struct {
+	BlockerID uuid.UUID
+	BlockedID uuid.UUID
+	CreatedAt time.Time
+}
+
+type Bookmark struct {
+	UserID    uuid.UUID
+	ChirpID   uuid.UUID
+	FolderID  uuid.NullUUID
+	CreatedAt time.Time
+}
+
+type BookmarkFolder struct {
 	ID        uuid.UUID
+	UserID    uuid.UUID
+	Name      string
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	Body      string
+}
+
+type Chirp struct {
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Body          string
+	UserID        uuid.UUID
+	LikeCount     int32
+	RechirpOfID   uuid.NullUUID
+	QuotedChirpID uuid.NullUUID
+	DeletedAt     sql.NullTime
+	Visibility    string
+	ExpiresAt     sql.NullTime
+	FannedOut     bool
+}
+
+type ChirpFlag struct {
+	ChirpID   uuid.UUID
+	CreatedAt time.Time
+	Words     []string
+}
+
+type ChirpHashtag struct {
+	ChirpID   uuid.UUID
+	HashtagID uuid.UUID
+}
+
+type Conversation struct {
+	ID            uuid.UUID
+	DirectKey     sql.NullString
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	LastMessageAt sql.NullTime
+}
+
+type ConversationParticipant struct {
+	ConversationID uuid.UUID
+	UserID         uuid.UUID
+	JoinedAt       time.Time
+	LastReadSeq    int64
+}
+
+type Draft struct {
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	UserID        uuid.UUID
+	Body          string
+	QuotedChirpID uuid.NullUUID
+	MediaIds      []uuid.UUID
+	PollOptions   []string
+	PollClosesAt  sql.NullTime
+	PublishAt     sql.NullTime
+	FailedAt      sql.NullTime
+	FailureReason sql.NullString
+	Visibility    string
+	ExpiresIn     sql.NullInt32
+	Attempts      int32
+	RetryAt       sql.NullTime
+}
+
+type FilterRule struct {
+	Word      string
+	Action    string
+	CreatedAt time.Time
+}
+
+type Follow struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+	CreatedAt  time.Time
+	Status     string
+}
+
+type Hashtag struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	Tag       string
+}
+
+type IdempotencyKey struct {
+	Scope               string
+	Key                 string
+	Fingerprint         string
+	CreatedAt           time.Time
+	ExpiresAt           time.Time
+	ResponseStatus      sql.NullInt32
+	ResponseContentType sql.NullString
+	ResponseBody        []byte
+}
+
+type Like struct {
 	UserID    uuid.UUID
+	ChirpID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type List struct {
+	ID          uuid.UUID
+	OwnerID     uuid.UUID
+	Name        string
+	Description string
+	IsPrivate   bool
+	MemberCount int32
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type ListMember struct {
+	ListID    uuid.UUID
+	UserID    uuid.UUID
+	CreatedAt time.Time
+}
+
+type MediaItem struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UserID       uuid.UUID
+	ChirpID      uuid.NullUUID
+	Position     int32
+	ContentType  string
+	BlobKey      string
+	ThumbnailKey string
+	Width        int32
+	Height       int32
+	SizeBytes    int64
+}
+
+type Mention struct {
+	ChirpID   uuid.UUID
+	UserID    uuid.UUID
+	CreatedAt time.Time
+	Handle    string
+}
+
+type Message struct {
+	ID             uuid.UUID
+	Seq            int64
+	ConversationID uuid.UUID
+	SenderID       uuid.UUID
+	Body           []byte
+	CreatedAt      time.Time
+}
+
+type Mute struct {
+	MuterID   uuid.UUID
+	MutedID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type Notification struct {
+	ID         uuid.UUID
+	Seq        int64
+	UserID     uuid.UUID
+	Type       string
+	ChirpID    uuid.NullUUID
+	GroupKey   string
+	ActorCount int32
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ReadAt     sql.NullTime
+}
+
+type NotificationActor struct {
+	NotificationID uuid.UUID
+	ActorID        uuid.UUID
+	CreatedAt      time.Time
+}
+
+type NotificationPreference struct {
+	UserID  uuid.UUID
+	Type    string
+	Enabled bool
+}
+
+type PinnedChirp struct {
+	UserID   uuid.UUID
+	ChirpID  uuid.UUID
+	PinnedAt time.Time
+}
+
+type Poll struct {
+	ID          uuid.UUID
+	ChirpID     uuid.UUID
+	CreatedAt   time.Time
+	ClosesAt    time.Time
+	FinalizedAt sql.NullTime
+}
+
+type PollOption struct {
+	ID        uuid.UUID
+	PollID    uuid.UUID
+	Position  int32
+	Label     string
+	VoteCount int32
+}
+
+type PollVote struct {
+	PollID    uuid.UUID
+	UserID    uuid.UUID
+	OptionID  uuid.UUID
+	CreatedAt time.Time
 }
 
 type RefreshToken struct {
@@ -28,11 +249,49 @@ type RefreshToken struct {
 	RevokedAt sql.NullTime
 }
 
+type TimelineBackfill struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+	CreatedAt  time.Time
+}
+
+type TimelineEntry struct {
+	UserID    uuid.UUID
+	ChirpID   uuid.UUID
+	AuthorID  uuid.UUID
+	CreatedAt time.Time
+}
+
+type TrendingChirp struct {
+	Period     string
+	ChirpID    uuid.UUID
+	Score      float64
+	ComputedAt time.Time
+}
+
+type TrendingHashtag struct {
+	Period     string
+	Tag        string
+	Score      float64
+	ComputedAt time.Time
+}
+
 type User struct {
-	ID             uuid.UUID
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
-	Email          string
-	HashedPassword string
-	IsChirpyRed    bool
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Email                string
+	HashedPassword       string
+	IsChirpyRed          bool
+	Handle               sql.NullString
+	DeletedAt            sql.NullTime
+	IsPrivate            bool
+	FollowerCount        int32
+	FollowingCount       int32
+	DisplayName          string
+	Bio                  string
+	Location             string
+	Website              string
+	AvatarID             uuid.NullUUID
+	DmsFromFollowingOnly bool
 }
@@ -17,7 +17,7 @@ SET updated_at = NOW(),
     email = $2,
     hashed_password = $3
 WHERE id = $1
-RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red
+RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, handle, deleted_at, is_private, follower_count, following_count, display_name, bio, location, website, avatar_id, dms_from_following_only
 `
 
 type UpdateUserParams struct {
@@ -36,6 +36,17 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.Handle,
+		&i.DeletedAt,
+		&i.IsPrivate,
+		&i.FollowerCount,
+		&i.FollowingCount,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Location,
+		&i.Website,
+		&i.AvatarID,
+		&i.DmsFromFollowingOnly,
 	)
 	return i, err
 }
@@ -16,7 +16,7 @@ UPDATE users
 SET updated_at = NOW(),
     is_chirpy_red = true
 WHERE id = $1
-RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red
+RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, handle, deleted_at, is_private, follower_count, following_count, display_name, bio, location, website, avatar_id, dms_from_following_only
 `
 
 func (q *Queries) UpgradeUserByID(ctx context.Context, id uuid.UUID) (User, error) {
@@ -29,6 +29,17 @@ func (q *Queries) UpgradeUserByID(ctx context.Context, id uuid.UUID) (User, erro
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.Handle,
+		&i.DeletedAt,
+		&i.IsPrivate,
+		&i.FollowerCount,
+		&i.FollowingCount,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Location,
+		&i.Website,
+		&i.AvatarID,
+		&i.DmsFromFollowingOnly,
 	)
 	return i, err
 }
@@ -0,0 +1,234 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	_ "image/gif"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// MaxDimension bounds the width and height of accepted images, so that a
+	// small compressed upload can't expand into a huge bitmap.
+	MaxDimension  = 8192
+	ThumbnailSize = 320
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrTooLarge        = errors.New("image dimensions too large")
+)
+
+// AllowedTypes are the MIME types accepted for upload, as detected from the
+// content rather than trusted from the client.
+var AllowedTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+type Image struct {
+	Data        []byte
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+}
+
+type Processed struct {
+	Original  Image
+	Thumbnail Image
+}
+
+// Process decodes an uploaded image and re-encodes it, which drops EXIF and
+// any other embedded metadata. The EXIF orientation of JPEGs is applied to
+// the pixels first so the image still displays the right way up. JPEGs stay
+// JPEGs and every other format becomes a PNG.
+func Process(data []byte) (Processed, error) {
+	contentType := http.DetectContentType(data)
+	allowed := false
+	for _, allowedType := range AllowedTypes {
+		if contentType == allowedType {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return Processed{}, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Processed{}, fmt.Errorf("failed to decode image config: %w", err)
+	}
+	if config.Width > MaxDimension || config.Height > MaxDimension {
+		return Processed{}, ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Processed{}, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	if contentType == "image/jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	original, err := encode(img, contentType)
+	if err != nil {
+		return Processed{}, err
+	}
+
+	thumbnail, err := encode(thumbnail(img), contentType)
+	if err != nil {
+		return Processed{}, err
+	}
+
+	return Processed{Original: original, Thumbnail: thumbnail}, nil
+}
+
+func encode(img image.Image, contentType string) (Image, error) {
+	var buf bytes.Buffer
+	result := Image{
+		Width:  img.Bounds().Dx(),
+		Height: img.Bounds().Dy(),
+	}
+
+	var err error
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+		result.ContentType, result.Extension = "image/jpeg", ".jpg"
+	} else {
+		err = png.Encode(&buf, img)
+		result.ContentType, result.Extension = "image/png", ".png"
+	}
+	if err != nil {
+		return Image{}, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	result.Data = buf.Bytes()
+	return result, nil
+}
+
+// thumbnail scales img down so that neither side exceeds ThumbnailSize.
+func thumbnail(img image.Image) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= ThumbnailSize && height <= ThumbnailSize {
+		return img
+	}
+
+	if width >= height {
+		height = max(1, height*ThumbnailSize/width)
+		width = ThumbnailSize
+	} else {
+		width = max(1, width*ThumbnailSize/height)
+		height = ThumbnailSize
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// orient applies an EXIF orientation (1 to 8) to img.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dstBounds := image.Rect(0, 0, width, height)
+	if orientation >= 5 {
+		dstBounds = image.Rect(0, 0, height, width)
+	}
+	dst := image.NewNRGBA(dstBounds)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = width-1-x, y
+			case 3:
+				dx, dy = width-1-x, height-1-y
+			case 4:
+				dx, dy = x, height-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = height-1-y, x
+			case 7:
+				dx, dy = height-1-y, width-1-x
+			case 8:
+				dx, dy = y, width-1-x
+			}
+			dst.Set(dx, dy, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// jpegOrientation returns the EXIF orientation tag of a JPEG, or 1 if it has
+// none.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if marker == 0xDA || length < 2 || i+2+length > len(data) {
+			return 1
+		}
+
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[offset : offset+2]))
+	for n := 0; n < entries; n++ {
+		entry := offset + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8 : entry+10]))
+		}
+	}
+	return 1
+}
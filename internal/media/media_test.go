@@ -0,0 +1,95 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// withOrientation inserts an EXIF APP1 segment carrying the given
+// orientation right after the SOI marker of a JPEG.
+func withOrientation(jpegData []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	result := append([]byte{}, jpegData[:2]...)
+	result = append(result, app1...)
+	return append(result, jpegData[2:]...)
+}
+
+func TestProcessAppliesOrientationAndStripsExif(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, img, nil)
+	if err != nil {
+		t.Fatalf(`failed to encode test image: %v`, err)
+	}
+	data := withOrientation(buf.Bytes(), 6)
+
+	if got := jpegOrientation(data); got != 6 {
+		t.Fatalf(`jpegOrientation = %d, expected 6`, got)
+	}
+
+	processed, err := Process(data)
+	if err != nil {
+		t.Fatalf(`failed to process image: %v`, err)
+	}
+
+	original := processed.Original
+	if original.Width != 20 || original.Height != 40 {
+		t.Errorf(`processed size = %dx%d, expected 20x40 after rotation`, original.Width, original.Height)
+	}
+	if original.ContentType != "image/jpeg" || original.Extension != ".jpg" {
+		t.Errorf(`processed type = %s (%s), expected image/jpeg (.jpg)`, original.ContentType, original.Extension)
+	}
+	if bytes.Contains(original.Data, []byte("Exif")) {
+		t.Errorf(`processed image still contains EXIF data`)
+	}
+}
+
+func TestProcessThumbnail(t *testing.T) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 1000, 500)))
+	if err != nil {
+		t.Fatalf(`failed to encode test image: %v`, err)
+	}
+
+	processed, err := Process(buf.Bytes())
+	if err != nil {
+		t.Fatalf(`failed to process image: %v`, err)
+	}
+
+	thumbnail := processed.Thumbnail
+	if thumbnail.Width != ThumbnailSize || thumbnail.Height != ThumbnailSize/2 {
+		t.Errorf(`thumbnail size = %dx%d, expected %dx%d`, thumbnail.Width, thumbnail.Height, ThumbnailSize, ThumbnailSize/2)
+	}
+	if thumbnail.ContentType != "image/png" {
+		t.Errorf(`thumbnail type = %s, expected image/png`, thumbnail.ContentType)
+	}
+}
+
+func TestProcessRejectsUnsupportedTypes(t *testing.T) {
+	_, err := Process([]byte("<html><body>not an image</body></html>"))
+	if err != ErrUnsupportedType {
+		t.Errorf(`Process(html) returned %v, expected ErrUnsupportedType`, err)
+	}
+}
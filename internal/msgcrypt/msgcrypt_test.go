@@ -0,0 +1,94 @@
+package msgcrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+func newTestCipher(t *testing.T, fill byte) *Cipher {
+	t.Helper()
+	c, err := NewCipher(bytes.Repeat([]byte{fill}, KeySize))
+	if err != nil {
+		t.Fatalf(`failed to create cipher: %v`, err)
+	}
+	return c
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	c := newTestCipher(t, 1)
+	plaintext := []byte("see you at noon")
+	additionalData := []byte("conversation-1")
+
+	sealed, err := c.Seal(plaintext, additionalData)
+	if err != nil {
+		t.Fatalf(`failed to seal: %v`, err)
+	}
+	if bytes.Contains(sealed, plaintext) {
+		t.Errorf(`sealed value contains the plaintext`)
+	}
+
+	opened, err := c.Open(sealed, additionalData)
+	if err != nil {
+		t.Fatalf(`failed to open: %v`, err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Errorf(`Open(Seal(%q)) = %q`, plaintext, opened)
+	}
+}
+
+func TestSealUsesFreshNonces(t *testing.T) {
+	c := newTestCipher(t, 1)
+	first, err1 := c.Seal([]byte("hi"), nil)
+	second, err2 := c.Seal([]byte("hi"), nil)
+	if err1 != nil || err2 != nil {
+		t.Fatalf(`failed to seal: %v, %v`, err1, err2)
+	}
+	if bytes.Equal(first, second) {
+		t.Errorf(`sealing the same plaintext twice gave the same ciphertext`)
+	}
+}
+
+func TestOpenRejects(t *testing.T) {
+	c := newTestCipher(t, 1)
+	sealed, err := c.Seal([]byte("secret"), []byte("conversation-1"))
+	if err != nil {
+		t.Fatalf(`failed to seal: %v`, err)
+	}
+
+	tampered := append([]byte{}, sealed...)
+	tampered[len(tampered)-1] ^= 1
+
+	cases := []struct {
+		name           string
+		cipher         *Cipher
+		sealed         []byte
+		additionalData []byte
+	}{
+		{"tampered", c, tampered, []byte("conversation-1")},
+		{"other additional data", c, sealed, []byte("conversation-2")},
+		{"other key", newTestCipher(t, 2), sealed, []byte("conversation-1")},
+		{"truncated", c, sealed[:4], []byte("conversation-1")},
+	}
+	for _, tc := range cases {
+		_, err := tc.cipher.Open(tc.sealed, tc.additionalData)
+		if err != ErrInvalidCiphertext {
+			t.Errorf(`%s: Open error = %v, expected ErrInvalidCiphertext`, tc.name, err)
+		}
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, KeySize)
+	parsed, err := ParseKey(base64.StdEncoding.EncodeToString(key))
+	if err != nil || !bytes.Equal(parsed, key) {
+		t.Errorf(`ParseKey = %v, %v, expected the encoded key`, parsed, err)
+	}
+
+	for _, encoded := range []string{"", "not base64!", base64.StdEncoding.EncodeToString(key[:16])} {
+		_, err := ParseKey(encoded)
+		if err == nil {
+			t.Errorf(`ParseKey(%q) succeeded, expected an error`, encoded)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+type like struct {
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (cfg *apiConfig) handlerPostChirpLikes(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		log.Printf("failed to parse chirpID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid chirp ID")
+		return
+	}
+
+	likedChirp, err := cfg.dbQueries.GetChirpByID(req.Context(), database.GetChirpByIDParams{
+		ID:       chirpID,
+		ViewerID: uuid.NullUUID{UUID: userID, Valid: true},
+	})
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to get chirp, Id not found: %s", err)
+		respondWithError(w, 404, "Chirp not found")
+		return
+	default:
+		log.Printf("failed to get chirp: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	created, err := cfg.dbQueries.CreateLike(req.Context(), database.CreateLikeParams{
+		UserID:  userID,
+		ChirpID: chirpID,
+	})
+	if err != nil {
+		log.Printf("failed to create like: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	// Liking again doesn't notify the author a second time. The like is
+	// already saved, so a failed notification is only logged.
+	if created > 0 {
+		err = notify(req.Context(), cfg.dbQueries, notificationLike, likedChirp.UserID, userID, uuid.NullUUID{UUID: chirpID, Valid: true})
+		if err != nil {
+			log.Printf("failed to notify chirp author: %s", err)
+		}
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerDeleteChirpLikes(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		log.Printf("failed to parse chirpID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid chirp ID")
+		return
+	}
+
+	err = cfg.dbQueries.DeleteLike(req.Context(), database.DeleteLikeParams{
+		UserID:  userID,
+		ChirpID: chirpID,
+	})
+	if err != nil {
+		log.Printf("failed to delete like: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerGetChirpLikes(w http.ResponseWriter, req *http.Request) {
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		log.Printf("failed to parse chirpID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid chirp ID")
+		return
+	}
+
+	_, err = cfg.dbQueries.GetChirpByID(req.Context(), database.GetChirpByIDParams{
+		ID:       chirpID,
+		ViewerID: cfg.viewerID(req),
+	})
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to get chirp, Id not found: %s", err)
+		respondWithError(w, 404, "Chirp not found")
+		return
+	default:
+		log.Printf("failed to get chirp: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	likes, err := cfg.dbQueries.GetLikesByChirpID(req.Context(), chirpID)
+	if err != nil {
+		log.Printf("failed to get likes: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := make([]like, len(likes))
+	for i, currentLike := range likes {
+		respBody[i] = like{
+			UserID:    currentLike.UserID,
+			CreatedAt: currentLike.CreatedAt,
+		}
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
@@ -0,0 +1,416 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/chirptext"
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	maxListNameLength        = 25
+	maxListDescriptionLength = 100
+)
+
+// list is a named set of accounts whose chirps can be read as a timeline.
+// Private lists are only visible to their owner.
+type list struct {
+	ID          uuid.UUID `json:"id"`
+	OwnerID     uuid.UUID `json:"owner_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsPrivate   bool      `json:"is_private"`
+	MemberCount int32     `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type listMember struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Handle  string    `json:"handle,omitempty"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+type listParameters struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	IsPrivate   bool   `json:"is_private"`
+}
+
+func convertList(dbList database.List) list {
+	return list{
+		ID:          dbList.ID,
+		OwnerID:     dbList.OwnerID,
+		Name:        dbList.Name,
+		Description: dbList.Description,
+		IsPrivate:   dbList.IsPrivate,
+		MemberCount: dbList.MemberCount,
+		CreatedAt:   dbList.CreatedAt,
+		UpdatedAt:   dbList.UpdatedAt,
+	}
+}
+
+// invalidListError reports a rejected list name or description. Its message
+// is shown to the client.
+type invalidListError struct {
+	msg string
+}
+
+func (e invalidListError) Error() string {
+	return e.msg
+}
+
+// decodeListParameters reads and normalizes the name and description of a
+// list. Its errors are shown to the client.
+func decodeListParameters(req *http.Request) (listParameters, error) {
+	params := listParameters{}
+	decoder := json.NewDecoder(req.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		return listParameters{}, invalidListError{"Invalid parameters"}
+	}
+
+	params.Name, err = chirptext.ValidateTextField(params.Name, maxListNameLength, false)
+	switch {
+	case err == chirptext.ErrFieldTooLong:
+		return listParameters{}, invalidListError{fmt.Sprintf("Name is too long, the limit is %d characters", maxListNameLength)}
+	case err != nil:
+		return listParameters{}, invalidListError{"Name contains control characters"}
+	case params.Name == "":
+		return listParameters{}, invalidListError{"Name can't be empty"}
+	}
+
+	params.Description, err = chirptext.ValidateTextField(params.Description, maxListDescriptionLength, true)
+	switch {
+	case err == chirptext.ErrFieldTooLong:
+		return listParameters{}, invalidListError{fmt.Sprintf("Description is too long, the limit is %d characters", maxListDescriptionLength)}
+	case err != nil:
+		return listParameters{}, invalidListError{"Description contains control characters"}
+	}
+	return params, nil
+}
+
+// visibleList loads the list named in the path. Private lists of other users
+// are reported as missing. It responds with an error itself when it returns
+// false.
+func (cfg *apiConfig) visibleList(w http.ResponseWriter, req *http.Request, viewerID uuid.NullUUID) (database.List, bool) {
+	listID, err := uuid.Parse(req.PathValue("listID"))
+	if err != nil {
+		log.Printf("failed to parse listID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid list ID")
+		return database.List{}, false
+	}
+
+	listByID, err := cfg.dbQueries.GetListByID(req.Context(), database.GetListByIDParams{
+		ID:       listID,
+		ViewerID: viewerID,
+	})
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to get list, Id not found: %s", err)
+		respondWithError(w, 404, "List not found")
+		return database.List{}, false
+	default:
+		log.Printf("failed to get list: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return database.List{}, false
+	}
+
+	return listByID, true
+}
+
+// ownedList authenticates the caller and loads the list named in the path,
+// which they must own. It responds with an error itself when it returns
+// false.
+func (cfg *apiConfig) ownedList(w http.ResponseWriter, req *http.Request) (database.List, bool) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return database.List{}, false
+	}
+
+	listByID, ok := cfg.visibleList(w, req, uuid.NullUUID{UUID: userID, Valid: true})
+	if !ok {
+		return database.List{}, false
+	}
+
+	if userID != listByID.OwnerID {
+		log.Printf("Not owner of the list")
+		respondWithError(w, 403, "Unauthorized")
+		return database.List{}, false
+	}
+
+	return listByID, true
+}
+
+func (cfg *apiConfig) handlerPostLists(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	params, err := decodeListParameters(req)
+	if err != nil {
+		log.Printf("failed to decode list: %s", err)
+		respondWithError(w, 400, err.Error())
+		return
+	}
+
+	createdList, err := cfg.dbQueries.CreateList(req.Context(), database.CreateListParams{
+		OwnerID:     userID,
+		Name:        params.Name,
+		Description: params.Description,
+		IsPrivate:   params.IsPrivate,
+	})
+	if err != nil {
+		log.Printf("failed to create list: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	respondWithJSON(w, 201, convertList(createdList))
+}
+
+func (cfg *apiConfig) handlerGetLists(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(req)
+	if err != nil {
+		log.Printf("failed to parse pagination: %s", err)
+		respondWithError(w, 400, "Invalid pagination")
+		return
+	}
+
+	lists, err := cfg.dbQueries.GetListsByOwnerID(req.Context(), database.GetListsByOwnerIDParams{
+		OwnerID: userID,
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if err != nil {
+		log.Printf("failed to get lists: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := make([]list, len(lists))
+	for i, currentList := range lists {
+		respBody[i] = convertList(currentList)
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
+
+func (cfg *apiConfig) handlerGetListsByID(w http.ResponseWriter, req *http.Request) {
+	listByID, ok := cfg.visibleList(w, req, cfg.viewerID(req))
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, 200, convertList(listByID))
+}
+
+func (cfg *apiConfig) handlerPutListsByID(w http.ResponseWriter, req *http.Request) {
+	listByID, ok := cfg.ownedList(w, req)
+	if !ok {
+		return
+	}
+
+	params, err := decodeListParameters(req)
+	if err != nil {
+		log.Printf("failed to decode list: %s", err)
+		respondWithError(w, 400, err.Error())
+		return
+	}
+
+	updatedList, err := cfg.dbQueries.UpdateList(req.Context(), database.UpdateListParams{
+		ID:          listByID.ID,
+		OwnerID:     listByID.OwnerID,
+		Name:        params.Name,
+		Description: params.Description,
+		IsPrivate:   params.IsPrivate,
+	})
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to update list, Id not found: %s", err)
+		respondWithError(w, 404, "List not found")
+		return
+	default:
+		log.Printf("failed to update list: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	respondWithJSON(w, 200, convertList(updatedList))
+}
+
+func (cfg *apiConfig) handlerDeleteListsByID(w http.ResponseWriter, req *http.Request) {
+	listByID, ok := cfg.ownedList(w, req)
+	if !ok {
+		return
+	}
+
+	_, err := cfg.dbQueries.DeleteList(req.Context(), database.DeleteListParams{
+		ID:      listByID.ID,
+		OwnerID: listByID.OwnerID,
+	})
+	if err != nil {
+		log.Printf("failed to delete list: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerPutListMember(w http.ResponseWriter, req *http.Request) {
+	listByID, ok := cfg.ownedList(w, req)
+	if !ok {
+		return
+	}
+
+	memberID, err := uuid.Parse(req.PathValue("userID"))
+	if err != nil {
+		log.Printf("failed to parse userID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid user ID")
+		return
+	}
+
+	// Users who blocked the owner, or were blocked by them, can't be added
+	// and are reported as missing.
+	added, err := cfg.dbQueries.AddListMember(req.Context(), database.AddListMemberParams{
+		ListID:  listByID.ID,
+		UserID:  memberID,
+		OwnerID: listByID.OwnerID,
+	})
+	if err != nil {
+		log.Printf("failed to add list member: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+	if added == 0 {
+		respondWithError(w, 404, "User not found")
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerDeleteListMember(w http.ResponseWriter, req *http.Request) {
+	listByID, ok := cfg.ownedList(w, req)
+	if !ok {
+		return
+	}
+
+	memberID, err := uuid.Parse(req.PathValue("userID"))
+	if err != nil {
+		log.Printf("failed to parse userID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid user ID")
+		return
+	}
+
+	err = cfg.dbQueries.DeleteListMember(req.Context(), database.DeleteListMemberParams{
+		ListID: listByID.ID,
+		UserID: memberID,
+	})
+	if err != nil {
+		log.Printf("failed to delete list member: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerGetListMembers(w http.ResponseWriter, req *http.Request) {
+	viewerID := cfg.viewerID(req)
+	listByID, ok := cfg.visibleList(w, req, viewerID)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(req)
+	if err != nil {
+		log.Printf("failed to parse pagination: %s", err)
+		respondWithError(w, 400, "Invalid pagination")
+		return
+	}
+
+	members, err := cfg.dbQueries.GetListMembers(req.Context(), database.GetListMembersParams{
+		ListID:   listByID.ID,
+		ViewerID: viewerID,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		log.Printf("failed to get list members: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := make([]listMember, len(members))
+	for i, member := range members {
+		respBody[i] = listMember{
+			UserID:  member.ID,
+			Handle:  member.Handle.String,
+			AddedAt: member.CreatedAt,
+		}
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
+
+func (cfg *apiConfig) handlerGetListChirps(w http.ResponseWriter, req *http.Request) {
+	viewerID := cfg.viewerID(req)
+	listByID, ok := cfg.visibleList(w, req, viewerID)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(req)
+	if err != nil {
+		log.Printf("failed to parse pagination: %s", err)
+		respondWithError(w, 400, "Invalid pagination")
+		return
+	}
+
+	newestFirst, err := parseSortOrder(req)
+	if err != nil {
+		log.Printf("failed to parse sort order: %s", err)
+		respondWithError(w, 400, "Invalid sort")
+		return
+	}
+
+	// Members' chirps go through the same visibility, block and mute
+	// filters as GET /api/chirps, for whoever is reading the list.
+	chirps, err := cfg.dbQueries.GetListChirps(req.Context(), database.GetListChirpsParams{
+		ListID:      listByID.ID,
+		ViewerID:    viewerID,
+		NewestFirst: newestFirst,
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		log.Printf("failed to get list chirps: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody, err := cfg.buildChirps(req.Context(), chirps, viewerID)
+	if err != nil {
+		log.Printf("failed to build chirps response: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
@@ -1,47 +1,102 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
-	"slices"
+	"os/signal"
 	"sort"
-	"strings"
+	"strconv"
+	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/LouisRemes-95/chirpy.git/internal/auth"
+	"github.com/LouisRemes-95/chirpy.git/internal/blobstore"
+	"github.com/LouisRemes-95/chirpy.git/internal/chirptext"
+	"github.com/LouisRemes-95/chirpy.git/internal/contentfilter"
 	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/LouisRemes-95/chirpy.git/internal/msgcrypt"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type user struct {
-	ID          uuid.UUID `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Email       string    `json:"email"`
-	IsChirpyRed bool      `json:"is_chirpy_red"`
+	ID             uuid.UUID `json:"id"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Email          string    `json:"email"`
+	IsChirpyRed    bool      `json:"is_chirpy_red"`
+	Handle         string    `json:"handle,omitempty"`
+	IsPrivate      bool      `json:"is_private"`
+	FollowerCount  int32     `json:"follower_count"`
+	FollowingCount int32     `json:"following_count"`
+}
+
+func buildUser(dbUser database.User) user {
+	return user{
+		ID:             dbUser.ID,
+		CreatedAt:      dbUser.CreatedAt,
+		UpdatedAt:      dbUser.UpdatedAt,
+		Email:          dbUser.Email,
+		IsChirpyRed:    dbUser.IsChirpyRed,
+		Handle:         dbUser.Handle.String,
+		IsPrivate:      dbUser.IsPrivate,
+		FollowerCount:  dbUser.FollowerCount,
+		FollowingCount: dbUser.FollowingCount,
+	}
 }
 
 type chirp struct {
-	ID        uuid.UUID `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Body      string    `json:"body"`
-	UserID    uuid.UUID `json:"user_id"`
+	ID          uuid.UUID         `json:"id"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	Body        string            `json:"body"`
+	UserID      uuid.UUID         `json:"user_id"`
+	Visibility  string            `json:"visibility"`
+	LikeCount   int32             `json:"like_count"`
+	LikedByMe   *bool             `json:"liked_by_me,omitempty"`
+	RechirpOf   *chirp            `json:"rechirp_of,omitempty"`
+	QuotedChirp *chirp            `json:"quoted_chirp,omitempty"`
+	Entities    []entity          `json:"entities"`
+	Media       []mediaAttachment `json:"media,omitempty"`
+	Poll        *poll             `json:"poll,omitempty"`
+	ExpiresAt   *time.Time        `json:"expires_at,omitempty"`
+	Pinned      bool              `json:"pinned,omitempty"`
+}
+
+type entity struct {
+	Type      string     `json:"type"`
+	Text      string     `json:"text"`
+	Tag       string     `json:"tag,omitempty"`
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+	Start     int        `json:"start"`
+	End       int        `json:"end"`
+	RuneStart int        `json:"rune_start"`
+	RuneEnd   int        `json:"rune_end"`
 }
 
 type apiConfig struct {
-	fileserverHits atomic.Int32
-	dbQueries      *database.Queries
-	platform       string
-	secret         string
-	polkaKey       string
+	fileserverHits  atomic.Int32
+	db              *sql.DB
+	dbQueries       *database.Queries
+	blobStore       blobstore.BlobStore
+	platform        string
+	secret          string
+	polkaKey        string
+	adminKey        string
+	duplicateWindow time.Duration
+	restoreWindow   time.Duration
+	contentFilter   contentfilter.ContentFilter
+	fanOutLimit     int32
+	messageCipher   *msgcrypt.Cipher
 }
 
 func (cfg *apiConfig) middlewareMetricsInc(next http.Handler) http.Handler {
@@ -72,6 +127,290 @@ func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.Write(data)
 }
 
+// respondWithDBError answers a failed database write. Constraint violations
+// become the client error they stem from and anything else a 500.
+func respondWithDBError(w http.ResponseWriter, err error) {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	switch pqErr.Code.Name() {
+	case "unique_violation":
+		respondWithError(w, 409, "Resource already exists")
+	case "foreign_key_violation":
+		respondWithError(w, 422, "Referenced resource does not exist")
+	case "not_null_violation", "check_violation", "string_data_right_truncation",
+		"invalid_text_representation", "numeric_value_out_of_range":
+		respondWithError(w, 400, "Invalid parameters")
+	default:
+		respondWithError(w, 500, "Internal server error")
+	}
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint
+// violation.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// parsePagination reads the limit and offset query parameters, applying the
+// default page size when limit is missing. Chirp listings are all paged this
+// way, in the order given by parseSortOrder. The home timeline is the one
+// exception and pages by cursor.
+func parsePagination(req *http.Request) (int32, int32, error) {
+	limit, err := parseLimit(req)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	offset := int64(0)
+	if offsetString := req.URL.Query().Get("offset"); offsetString != "" {
+		offset, err = strconv.ParseInt(offsetString, 10, 32)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("invalid offset %q", offsetString)
+		}
+	}
+
+	return limit, int32(offset), nil
+}
+
+// parseLimit reads the page size of endpoints paginated by cursor rather
+// than by offset.
+func parseLimit(req *http.Request) (int32, error) {
+	limitString := req.URL.Query().Get("limit")
+	if limitString == "" {
+		return defaultPageSize, nil
+	}
+
+	limit, err := strconv.ParseInt(limitString, 10, 32)
+	if err != nil || limit < 1 || limit > maxPageSize {
+		return 0, fmt.Errorf("invalid limit %q", limitString)
+	}
+	return int32(limit), nil
+}
+
+// parseSortOrder reads the sort query parameter of chirp listings and reports
+// whether the newest chirps come first. It is "asc", the default, or "desc".
+func parseSortOrder(req *http.Request) (bool, error) {
+	switch sortOrder := req.URL.Query().Get("sort"); sortOrder {
+	case "", "asc":
+		return false, nil
+	case "desc":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid sort %q", sortOrder)
+	}
+}
+
+// authenticate returns the ID of the user the request's access token was
+// issued to. Tokens of deleted accounts are refused before they expire. It
+// responds with an error itself when it returns false.
+func (cfg *apiConfig) authenticate(w http.ResponseWriter, req *http.Request) (uuid.UUID, bool) {
+	userID, err := auth.Authenticate(req.Context(), req.Header, cfg.secret, cfg.dbQueries.IsUserActive)
+	if errors.Is(err, auth.ErrUnauthorized) {
+		log.Printf("failed to authenticate: %v", err)
+		respondWithError(w, 401, "Unauthorized")
+		return uuid.Nil, false
+	}
+	if err != nil {
+		log.Printf("failed to authenticate: %v", err)
+		respondWithError(w, 500, "Internal server error")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// viewerID returns the ID of the authenticated user, if the request carries a
+// valid access token. Endpoints that are public but personalise their
+// response use it instead of rejecting anonymous requests.
+func (cfg *apiConfig) viewerID(req *http.Request) uuid.NullUUID {
+	if req.Header.Get("Authorization") == "" {
+		return uuid.NullUUID{}
+	}
+
+	userID, err := auth.Authenticate(req.Context(), req.Header, cfg.secret, cfg.dbQueries.IsUserActive)
+	if err != nil {
+		log.Printf("ignoring invalid token string: %v", err)
+		return uuid.NullUUID{}
+	}
+
+	return uuid.NullUUID{UUID: userID, Valid: true}
+}
+
+// buildChirps converts database chirps into their JSON representation,
+// embedding the chirps they rechirp or quote. If viewerID is valid,
+// liked_by_me is filled in for that user.
+func (cfg *apiConfig) buildChirps(ctx context.Context, chirps []database.Chirp, viewerID uuid.NullUUID) ([]chirp, error) {
+	respBody, err := cfg.convertChirps(ctx, chirps, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var referencedIDs []uuid.UUID
+	for _, currentChirp := range chirps {
+		if currentChirp.RechirpOfID.Valid {
+			referencedIDs = append(referencedIDs, currentChirp.RechirpOfID.UUID)
+		}
+		if currentChirp.QuotedChirpID.Valid {
+			referencedIDs = append(referencedIDs, currentChirp.QuotedChirpID.UUID)
+		}
+	}
+	if len(referencedIDs) == 0 {
+		return respBody, nil
+	}
+
+	referencedChirps, err := cfg.dbQueries.GetChirpsByIDs(ctx, database.GetChirpsByIDsParams{
+		Ids:      referencedIDs,
+		ViewerID: viewerID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referenced chirps: %w", err)
+	}
+
+	referencedBody, err := cfg.convertChirps(ctx, referencedChirps, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := make(map[uuid.UUID]*chirp, len(referencedBody))
+	for i := range referencedBody {
+		referenced[referencedBody[i].ID] = &referencedBody[i]
+	}
+
+	for i, currentChirp := range chirps {
+		if currentChirp.RechirpOfID.Valid {
+			respBody[i].RechirpOf = referenced[currentChirp.RechirpOfID.UUID]
+		}
+		if currentChirp.QuotedChirpID.Valid {
+			respBody[i].QuotedChirp = referenced[currentChirp.QuotedChirpID.UUID]
+		}
+	}
+
+	return respBody, nil
+}
+
+// convertChirps converts database chirps into their JSON representation
+// without resolving rechirped or quoted chirps.
+func (cfg *apiConfig) convertChirps(ctx context.Context, chirps []database.Chirp, viewerID uuid.NullUUID) ([]chirp, error) {
+	chirpIDs := make([]uuid.UUID, len(chirps))
+	for i, currentChirp := range chirps {
+		chirpIDs[i] = currentChirp.ID
+	}
+
+	mentioned := map[uuid.UUID]map[string]uuid.UUID{}
+	if len(chirps) > 0 {
+		mentions, err := cfg.dbQueries.GetMentionsByChirpIDs(ctx, chirpIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get mentions: %w", err)
+		}
+		for _, mention := range mentions {
+			if mentioned[mention.ChirpID] == nil {
+				mentioned[mention.ChirpID] = map[string]uuid.UUID{}
+			}
+			mentioned[mention.ChirpID][mention.Handle] = mention.UserID
+		}
+	}
+
+	attachments := map[uuid.UUID][]mediaAttachment{}
+	if len(chirps) > 0 {
+		mediaItems, err := cfg.dbQueries.GetMediaItemsByChirpIDs(ctx, chirpIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get media: %w", err)
+		}
+		for _, item := range mediaItems {
+			attachments[item.ChirpID.UUID] = append(attachments[item.ChirpID.UUID], buildMediaAttachment(item))
+		}
+	}
+
+	polls, err := cfg.loadPolls(ctx, chirpIDs, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	liked := map[uuid.UUID]bool{}
+	if viewerID.Valid && len(chirps) > 0 {
+		likedIDs, err := cfg.dbQueries.GetLikedChirpIDs(ctx, database.GetLikedChirpIDsParams{
+			UserID:   viewerID.UUID,
+			ChirpIds: chirpIDs,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get liked chirps: %w", err)
+		}
+		for _, likedID := range likedIDs {
+			liked[likedID] = true
+		}
+	}
+
+	respBody := make([]chirp, len(chirps))
+	for i, currentChirp := range chirps {
+		respBody[i] = chirp{
+			ID:         currentChirp.ID,
+			CreatedAt:  currentChirp.CreatedAt,
+			UpdatedAt:  currentChirp.UpdatedAt,
+			Body:       currentChirp.Body,
+			UserID:     currentChirp.UserID,
+			Visibility: currentChirp.Visibility,
+			LikeCount:  currentChirp.LikeCount,
+			Entities:   buildEntities(currentChirp.Body, mentioned[currentChirp.ID]),
+			Media:      attachments[currentChirp.ID],
+			Poll:       polls[currentChirp.ID],
+		}
+		if currentChirp.ExpiresAt.Valid {
+			respBody[i].ExpiresAt = &currentChirp.ExpiresAt.Time
+		}
+		if viewerID.Valid {
+			likedByMe := liked[currentChirp.ID]
+			respBody[i].LikedByMe = &likedByMe
+		}
+	}
+
+	return respBody, nil
+}
+
+// buildEntities lists the hashtags of body and the mentions that were
+// resolved to a user when the chirp was created, ordered by position.
+func buildEntities(body string, mentioned map[string]uuid.UUID) []entity {
+	entities := []entity{}
+	for _, hashtag := range chirptext.ExtractHashtags(body) {
+		entities = append(entities, entity{
+			Type:      hashtag.Type,
+			Text:      hashtag.Text,
+			Tag:       hashtag.Value,
+			Start:     hashtag.Start,
+			End:       hashtag.End,
+			RuneStart: hashtag.RuneStart,
+			RuneEnd:   hashtag.RuneEnd,
+		})
+	}
+	for _, mention := range chirptext.ExtractMentions(body) {
+		userID, ok := mentioned[mention.Value]
+		if !ok {
+			continue
+		}
+		entities = append(entities, entity{
+			Type:      mention.Type,
+			Text:      mention.Text,
+			UserID:    &userID,
+			Start:     mention.Start,
+			End:       mention.End,
+			RuneStart: mention.RuneStart,
+			RuneEnd:   mention.RuneEnd,
+		})
+	}
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i].Start < entities[j].Start
+	})
+	return entities
+}
+
 // Handler functions
 
 func (cfg *apiConfig) handlerGetMetrics(w http.ResponseWriter, req *http.Request) {
@@ -130,6 +469,7 @@ func (cfg *apiConfig) handlerPostUser(w http.ResponseWriter, req *http.Request)
 	type parameters struct {
 		Password string `json:"password"`
 		Email    string `json:"email"`
+		Handle   string `json:"handle"`
 	}
 
 	decoder := json.NewDecoder(req.Body)
@@ -141,6 +481,11 @@ func (cfg *apiConfig) handlerPostUser(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
+	if params.Handle != "" && !chirptext.ValidHandle(params.Handle) {
+		respondWithError(w, 400, "Invalid handle")
+		return
+	}
+
 	HashedPassword, err := auth.HashPassword(params.Password)
 	if err != nil {
 		log.Printf("failed to hash password: %s", err)
@@ -151,101 +496,363 @@ func (cfg *apiConfig) handlerPostUser(w http.ResponseWriter, req *http.Request)
 	myParams := database.CreateUserParams{
 		Email:          params.Email,
 		HashedPassword: HashedPassword,
+		Handle:         sql.NullString{String: params.Handle, Valid: params.Handle != ""},
 	}
 
 	createdUser, err := cfg.dbQueries.CreateUser(req.Context(), myParams)
+	if isUniqueViolation(err) {
+		log.Printf("failed to create user: %s", err)
+		respondWithError(w, 409, "Email or handle already taken")
+		return
+	}
 	if err != nil {
 		log.Printf("failed to create user: %s", err)
-		respondWithError(w, 500, "Internal server error")
+		respondWithDBError(w, err)
 		return
 	}
 
-	respBody := user{
-		ID:          createdUser.ID,
-		CreatedAt:   createdUser.CreatedAt,
-		UpdatedAt:   createdUser.UpdatedAt,
-		Email:       createdUser.Email,
-		IsChirpyRed: createdUser.IsChirpyRed,
-	}
+	respBody := buildUser(createdUser)
 
 	respondWithJSON(w, 201, respBody)
 }
 
-func (cfg *apiConfig) handlerPostChirp(w http.ResponseWriter, req *http.Request) {
-	tokenString, err := auth.GetBearerToken(req.Header)
+// chirpParameters describes a chirp to post, either right away or later from
+// a draft.
+type chirpParameters struct {
+	Body          string          `json:"body"`
+	QuotedChirpID uuid.NullUUID   `json:"quoted_chirp_id"`
+	MediaIDs      []uuid.UUID     `json:"media_ids"`
+	Poll          *pollParameters `json:"poll"`
+	Visibility    string          `json:"visibility"`
+	ExpiresIn     *int32          `json:"expires_in"`
+}
+
+// Bounds of expires_in for ephemeral chirps.
+const (
+	minChirpLifetime = time.Minute
+	maxChirpLifetime = 30 * 24 * time.Hour
+)
+
+// Who may read a chirp: anyone, the author's followers, or only the author.
+const (
+	visibilityPublic    = "public"
+	visibilityFollowers = "followers"
+	visibilityPrivate   = "private"
+)
+
+// normalizeVisibility defaults an unset visibility to public.
+func normalizeVisibility(visibility string) (string, error) {
+	switch visibility {
+	case "":
+		return visibilityPublic, nil
+	case visibilityPublic, visibilityFollowers, visibilityPrivate:
+		return visibility, nil
+	default:
+		return "", invalidChirpError{"Visibility must be public, followers or private"}
+	}
+}
+
+// invalidChirpError reports a chirp rejected because of its content. Its
+// message is safe to show to the author.
+type invalidChirpError struct {
+	msg string
+}
+
+func (e invalidChirpError) Error() string {
+	return e.msg
+}
+
+// duplicateChirpError is returned when the author already posted the same
+// chirp within the duplicate window.
+type duplicateChirpError struct {
+	existing database.Chirp
+}
+
+func (e duplicateChirpError) Error() string {
+	return fmt.Sprintf("duplicate of chirp %s", e.existing.ID)
+}
+
+// errRejectedContent is returned for chirps matching a reject rule of the
+// content filter.
+var errRejectedContent = errors.New("chirp contains prohibited content")
+
+// validate runs the checks that don't need the database, as of now, and
+// normalizes the body to the form it is stored in.
+func (p *chirpParameters) validate(now time.Time) error {
+	body, err := chirptext.ValidateBody(p.Body)
+	switch err {
+	case nil:
+		p.Body = body
+	case chirptext.ErrEmptyBody:
+		return invalidChirpError{"Chirp is empty"}
+	case chirptext.ErrBodyTooLong:
+		return invalidChirpError{fmt.Sprintf("Chirp is too long, the limit is %d characters", chirptext.MaxLength)}
+	case chirptext.ErrControlCharacter:
+		return invalidChirpError{"Chirp contains control characters"}
+	default:
+		return invalidChirpError{"Invalid chirp"}
+	}
+
+	p.Visibility, err = normalizeVisibility(p.Visibility)
 	if err != nil {
-		log.Printf("failed to get bearer token: %v", err)
-		respondWithError(w, 401, "Unauthorized")
-		return
+		return err
+	}
+
+	if len(p.MediaIDs) > maxMediaPerChirp {
+		return invalidChirpError{"Too many media attachments"}
+	}
+
+	if p.ExpiresIn != nil {
+		lifetime := time.Duration(*p.ExpiresIn) * time.Second
+		if lifetime < minChirpLifetime || lifetime > maxChirpLifetime {
+			return invalidChirpError{fmt.Sprintf("expires_in must be between %d and %d seconds", int(minChirpLifetime.Seconds()), int(maxChirpLifetime.Seconds()))}
+		}
 	}
 
-	userID, err := auth.ValidateJWT(tokenString, cfg.secret)
+	if p.Poll != nil {
+		err = p.Poll.validate(now)
+		if err != nil {
+			return invalidChirpError{"Invalid poll: " + err.Error()}
+		}
+	}
+	return nil
+}
+
+// createChirp validates and filters params and stores the chirp together with
+// its hashtags, mentions, media and poll. q should be bound to a transaction so
+// that a rejected chirp leaves nothing behind.
+func (cfg *apiConfig) createChirp(ctx context.Context, q *database.Queries, userID uuid.UUID, params chirpParameters, now time.Time) (database.Chirp, error) {
+	err := params.validate(now)
 	if err != nil {
-		log.Printf("failed to validate token string: %v", err)
-		respondWithError(w, 401, "Unauthorized")
+		return database.Chirp{}, err
+	}
+
+	filtered := cfg.contentFilter.Filter(params.Body)
+	if filtered.Rejected() {
+		return database.Chirp{}, errRejectedContent
+	}
+
+	var quotedChirp database.Chirp
+	if params.QuotedChirpID.Valid {
+		quotedChirp, err = q.GetChirpByID(ctx, database.GetChirpByIDParams{
+			ID:       params.QuotedChirpID.UUID,
+			ViewerID: uuid.NullUUID{UUID: userID, Valid: true},
+		})
+		if err == sql.ErrNoRows {
+			return database.Chirp{}, invalidChirpError{"Quoted chirp not found"}
+		}
+		if err != nil {
+			return database.Chirp{}, fmt.Errorf("failed to get quoted chirp: %w", err)
+		}
+
+		if quotedChirp.RechirpOfID.Valid {
+			params.QuotedChirpID = quotedChirp.RechirpOfID
+			quotedChirp, err = q.GetChirpByID(ctx, database.GetChirpByIDParams{
+				ID:       params.QuotedChirpID.UUID,
+				ViewerID: uuid.NullUUID{UUID: userID, Valid: true},
+			})
+			if err == sql.ErrNoRows {
+				return database.Chirp{}, invalidChirpError{"Quoted chirp not found"}
+			}
+			if err != nil {
+				return database.Chirp{}, fmt.Errorf("failed to get quoted chirp: %w", err)
+			}
+		}
+	}
+
+	if cfg.duplicateWindow > 0 {
+		// The lock serializes the author's posts until the transaction ends,
+		// so two retries of the same request can't both miss each other.
+		err = q.LockUserChirps(ctx, userID)
+		if err != nil {
+			return database.Chirp{}, fmt.Errorf("failed to lock user chirps: %w", err)
+		}
+
+		recentChirps, err := q.GetRecentChirpsByAuthor(ctx, database.GetRecentChirpsByAuthorParams{
+			UserID:    userID,
+			CreatedAt: now.Add(-cfg.duplicateWindow).UTC(),
+		})
+		if err != nil {
+			return database.Chirp{}, fmt.Errorf("failed to get recent chirps: %w", err)
+		}
+		// The quote is resolved by now, so quoting a rechirp and quoting the
+		// chirp it points to count as the same chirp.
+		for _, recent := range recentChirps {
+			if chirptext.SameBody(recent.Body, filtered.Text) && recent.QuotedChirpID == params.QuotedChirpID {
+				return database.Chirp{}, duplicateChirpError{existing: recent}
+			}
+		}
+	}
+
+	var expiresAt sql.NullTime
+	if params.ExpiresIn != nil {
+		expiresAt = sql.NullTime{Time: now.Add(time.Duration(*params.ExpiresIn) * time.Second).UTC(), Valid: true}
+	}
+
+	createdChirp, err := q.CreateChirp(ctx, database.CreateChirpParams{
+		Body:          filtered.Text,
+		UserID:        userID,
+		QuotedChirpID: params.QuotedChirpID,
+		Visibility:    params.Visibility,
+		ExpiresAt:     expiresAt,
+	})
+	if err != nil {
+		return database.Chirp{}, fmt.Errorf("failed to create chirp: %w", err)
+	}
+
+	if filtered.Flagged() {
+		err = q.CreateChirpFlag(ctx, database.CreateChirpFlagParams{
+			ChirpID: createdChirp.ID,
+			Words:   filtered.Words(contentfilter.ActionFlag),
+		})
+		if err != nil {
+			return database.Chirp{}, fmt.Errorf("failed to flag chirp: %w", err)
+		}
+	}
+
+	err = saveHashtags(ctx, q, createdChirp)
+	if err != nil {
+		return database.Chirp{}, fmt.Errorf("failed to save hashtags: %w", err)
+	}
+
+	err = saveMentions(ctx, q, createdChirp)
+	if err != nil {
+		return database.Chirp{}, fmt.Errorf("failed to save mentions: %w", err)
+	}
+
+	err = attachMedia(ctx, q, createdChirp, params.MediaIDs)
+	if errors.Is(err, errInvalidMedia) {
+		return database.Chirp{}, invalidChirpError{"Invalid media"}
+	}
+	if err != nil {
+		return database.Chirp{}, fmt.Errorf("failed to attach media: %w", err)
+	}
+
+	if params.Poll != nil {
+		err = savePoll(ctx, q, createdChirp, *params.Poll)
+		if err != nil {
+			return database.Chirp{}, fmt.Errorf("failed to save poll: %w", err)
+		}
+	}
+
+	err = q.FanOutChirp(ctx, database.FanOutChirpParams{
+		ChirpID:     createdChirp.ID,
+		FanoutLimit: cfg.fanOutLimit,
+	})
+	if err != nil {
+		return database.Chirp{}, fmt.Errorf("failed to fan out chirp: %w", err)
+	}
+
+	if params.QuotedChirpID.Valid {
+		err = notify(ctx, q, notificationQuote, quotedChirp.UserID, userID, params.QuotedChirpID)
+		if err != nil {
+			return database.Chirp{}, fmt.Errorf("failed to notify quoted author: %w", err)
+		}
+	}
+
+	return createdChirp, nil
+}
+
+func (cfg *apiConfig) handlerPostChirp(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
 		return
 	}
 
 	type parameters struct {
-		Body   string    `json:"body"`
+		chirpParameters
 		UserID uuid.UUID `json:"user_id"`
 	}
 
 	decoder := json.NewDecoder(req.Body)
 	params := parameters{}
-	err = decoder.Decode(&params)
+	err := decoder.Decode(&params)
 	if err != nil {
 		log.Printf("failed to decode parameters: %s", err)
 		respondWithError(w, 500, "Internal server error")
 		return
 	}
 
-	if len(params.Body) > 140 {
-		respondWithError(w, 400, "Chirp is too long")
+	tx, err := cfg.db.BeginTx(req.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %s", err)
+		respondWithError(w, 500, "Internal server error")
 		return
 	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
 
-	chirpParams := database.CreateChirpParams{
-		Body:   cleanMessage(params.Body),
-		UserID: userID,
+	var invalidErr invalidChirpError
+	createdChirp, err := cfg.createChirp(req.Context(), qtx, userID, params.chirpParameters, time.Now())
+	if errors.As(err, &invalidErr) {
+		log.Printf("failed to create chirp: %s", err)
+		respondWithError(w, 400, invalidErr.msg)
+		return
+	}
+	if errors.Is(err, errRejectedContent) {
+		respondWithError(w, 422, "Chirp contains prohibited content")
+		return
+	}
+	var duplicateErr duplicateChirpError
+	if errors.As(err, &duplicateErr) {
+		respBody, err := cfg.buildChirps(req.Context(), []database.Chirp{duplicateErr.existing}, uuid.NullUUID{UUID: userID, Valid: true})
+		if err != nil {
+			log.Printf("failed to build chirp response: %s", err)
+			respondWithError(w, 500, "Internal server error")
+			return
+		}
+		respondWithJSON(w, 409, respBody[0])
+		return
 	}
-
-	createdChirp, err := cfg.dbQueries.CreateChirp(req.Context(), chirpParams)
 	if err != nil {
 		log.Printf("failed to create chirp: %s", err)
-		respondWithError(w, 500, "Internal server error")
+		respondWithDBError(w, err)
 		return
 	}
 
-	respBody := chirp{
-		ID:        createdChirp.ID,
-		CreatedAt: createdChirp.CreatedAt,
-		UpdatedAt: createdChirp.UpdatedAt,
-		Body:      createdChirp.Body,
-		UserID:    createdChirp.UserID,
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("failed to commit chirp: %s", err)
+		respondWithDBError(w, err)
+		return
 	}
 
-	respondWithJSON(w, 201, respBody)
-}
-
-func cleanMessage(s string) string {
-	badWords := []string{"kerfuffle", "sharbert", "fornax"}
-	words := strings.Split(s, " ")
-	for i, word := range words {
-		if slices.Contains(badWords, strings.ToLower(word)) {
-			words[i] = "****"
-		}
+	respBody, err := cfg.buildChirps(req.Context(), []database.Chirp{createdChirp}, uuid.NullUUID{UUID: userID, Valid: true})
+	if err != nil {
+		log.Printf("failed to build chirp response: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
 	}
-	return strings.Join(words, " ")
+
+	respondWithJSON(w, 201, respBody[0])
 }
 
 func (cfg *apiConfig) handlerGetChirps(w http.ResponseWriter, req *http.Request) {
 	authorIDString := req.URL.Query().Get("author_id")
+	viewerID := cfg.viewerID(req)
 
-	var chirps []database.Chirp
-	var err error
+	limit, offset, err := parsePagination(req)
+	if err != nil {
+		log.Printf("failed to parse pagination: %s", err)
+		respondWithError(w, 400, "Invalid pagination")
+		return
+	}
+
+	newestFirst, err := parseSortOrder(req)
+	if err != nil {
+		log.Printf("failed to parse sort order: %s", err)
+		respondWithError(w, 400, "Invalid sort")
+		return
+	}
+
+	var chirps, pinned []database.Chirp
 	if len(authorIDString) == 0 {
-		chirps, err = cfg.dbQueries.GetChirps(req.Context())
+		chirps, err = cfg.dbQueries.GetChirps(req.Context(), database.GetChirpsParams{
+			ViewerID:    viewerID,
+			NewestFirst: newestFirst,
+			Limit:       limit,
+			Offset:      offset,
+		})
 
 	} else {
 		var AuthorID uuid.UUID
@@ -255,7 +862,21 @@ func (cfg *apiConfig) handlerGetChirps(w http.ResponseWriter, req *http.Request)
 			respondWithError(w, 400, "Invalid user ID")
 			return
 		}
-		chirps, err = cfg.dbQueries.GetChirpsByAuthorID(req.Context(), AuthorID)
+		// Pinned chirps are left out of the pages and come before the
+		// first one, whatever the sort order.
+		chirps, err = cfg.dbQueries.GetChirpsByAuthorID(req.Context(), database.GetChirpsByAuthorIDParams{
+			UserID:      AuthorID,
+			ViewerID:    viewerID,
+			NewestFirst: newestFirst,
+			Limit:       limit,
+			Offset:      offset,
+		})
+		if err == nil && offset == 0 {
+			pinned, err = cfg.dbQueries.GetPinnedChirps(req.Context(), database.GetPinnedChirpsParams{
+				UserID:   AuthorID,
+				ViewerID: viewerID,
+			})
+		}
 	}
 
 	if err != nil {
@@ -264,23 +885,14 @@ func (cfg *apiConfig) handlerGetChirps(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	sortOrder := req.URL.Query().Get("sort")
-
-	if sortOrder == "desc" {
-		sort.Slice(chirps, func(i, j int) bool {
-			return chirps[i].CreatedAt.After(chirps[j].CreatedAt)
-		})
+	respBody, err := cfg.buildChirps(req.Context(), append(pinned, chirps...), viewerID)
+	if err != nil {
+		log.Printf("failed to build chirps response: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
 	}
-
-	respBody := make([]chirp, len(chirps))
-	for i, currentChirp := range chirps {
-		respBody[i] = chirp{
-			ID:        currentChirp.ID,
-			CreatedAt: currentChirp.CreatedAt,
-			UpdatedAt: currentChirp.UpdatedAt,
-			Body:      currentChirp.Body,
-			UserID:    currentChirp.UserID,
-		}
+	for i := range pinned {
+		respBody[i].Pinned = true
 	}
 
 	respondWithJSON(w, 200, respBody)
@@ -294,7 +906,13 @@ func (cfg *apiConfig) handlerGetChirpsByID(w http.ResponseWriter, req *http.Requ
 		return
 	}
 
-	chirpByID, err := cfg.dbQueries.GetChirpByID(req.Context(), chirpID)
+	// Chirps the viewer may not see are reported as missing, so that their
+	// existence isn't leaked.
+	viewerID := cfg.viewerID(req)
+	chirpByID, err := cfg.dbQueries.GetChirpByID(req.Context(), database.GetChirpByIDParams{
+		ID:       chirpID,
+		ViewerID: viewerID,
+	})
 	switch err {
 	case nil:
 	case sql.ErrNoRows:
@@ -307,15 +925,37 @@ func (cfg *apiConfig) handlerGetChirpsByID(w http.ResponseWriter, req *http.Requ
 		return
 	}
 
-	respBody := chirp{
-		ID:        chirpByID.ID,
-		CreatedAt: chirpByID.CreatedAt,
-		UpdatedAt: chirpByID.UpdatedAt,
-		Body:      chirpByID.Body,
-		UserID:    chirpByID.UserID,
+	respBody, err := cfg.buildChirps(req.Context(), []database.Chirp{chirpByID}, viewerID)
+	if err != nil {
+		log.Printf("failed to build chirp response: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respondWithJSON(w, 200, respBody[0])
+}
+
+// createSession issues an access token and a refresh token for the user.
+func (cfg *apiConfig) createSession(ctx context.Context, q *database.Queries, userID uuid.UUID) (string, string, error) {
+	token, err := auth.MakeJWT(userID, cfg.secret, time.Hour)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to make JWT token: %w", err)
 	}
 
-	respondWithJSON(w, 200, respBody)
+	refreshToken, err := auth.MakeRefreshToken()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to make refresh token: %w", err)
+	}
+
+	_, err = q.CreateRefreshToken(ctx, database.CreateRefreshTokenParams{
+		Token:  refreshToken,
+		UserID: userID,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create refresh token in database: %w", err)
+	}
+
+	return token, refreshToken, nil
 }
 
 func (cfg *apiConfig) handlerPostLogin(w http.ResponseWriter, req *http.Request) {
@@ -347,27 +987,10 @@ func (cfg *apiConfig) handlerPostLogin(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	token, err := auth.MakeJWT(returnedUser.ID, cfg.secret, time.Hour)
+	token, refreshToken, err := cfg.createSession(req.Context(), cfg.dbQueries, returnedUser.ID)
 	if err != nil {
-		log.Printf("failed to make JWT token: %v", err)
-		respondWithError(w, 500, "Internal server error")
-		return
-	}
-
-	refreshToken, err := auth.MakeRefreshToken()
-	if err != nil {
-		log.Printf("failed to make refresh token: %v", err)
-		respondWithError(w, 500, "Internal server error")
-		return
-	}
-
-	_, err = cfg.dbQueries.CreateRefreshToken(req.Context(), database.CreateRefreshTokenParams{
-		Token:  refreshToken,
-		UserID: returnedUser.ID,
-	})
-	if err != nil {
-		log.Printf("failed to create refresh token in database: %v", err)
-		respondWithError(w, 500, "Internal server error")
+		log.Printf("failed to create session: %v", err)
+		respondWithDBError(w, err)
 		return
 	}
 
@@ -376,13 +999,7 @@ func (cfg *apiConfig) handlerPostLogin(w http.ResponseWriter, req *http.Request)
 		Token        string `json:"token"`
 		RefreshToken string `json:"refresh_token"`
 	}{
-		user: user{
-			ID:          returnedUser.ID,
-			CreatedAt:   returnedUser.CreatedAt,
-			UpdatedAt:   returnedUser.UpdatedAt,
-			Email:       returnedUser.Email,
-			IsChirpyRed: returnedUser.IsChirpyRed,
-		},
+		user:         buildUser(returnedUser),
 		Token:        token,
 		RefreshToken: refreshToken,
 	}
@@ -454,32 +1071,44 @@ func (cfg *apiConfig) handlerPostRevoke(w http.ResponseWriter, req *http.Request
 func (cfg *apiConfig) handlerPutUsers(w http.ResponseWriter, req *http.Request) {
 	log.Print("HELLO")
 
-	tokenString, err := auth.GetBearerToken(req.Header)
-	if err != nil {
-		log.Printf("failed to get bearer token: %v", err)
-		respondWithError(w, 401, "Unauthorized")
-		return
-	}
-
-	userID, err := auth.ValidateJWT(tokenString, cfg.secret)
-	if err != nil {
-		log.Printf("failed to validate token string: %v", err)
-		respondWithError(w, 401, "Unauthorized")
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
 		return
 	}
 
 	decoder := json.NewDecoder(req.Body)
 	params := struct {
-		Password string `json:"password"`
-		Email    string `json:"email"`
+		CurrentPassword string `json:"current_password"`
+		Password        string `json:"password"`
+		Email           string `json:"email"`
+		Handle          string `json:"handle"`
+		IsPrivate       *bool  `json:"is_private"`
 	}{}
-	err = decoder.Decode(&params)
+	err := decoder.Decode(&params)
 	if err != nil {
 		log.Printf("failed to decode parameters: %s", err)
 		respondWithError(w, 500, "Internal server error")
 		return
 	}
 
+	if params.Handle != "" && !chirptext.ValidHandle(params.Handle) {
+		respondWithError(w, 400, "Invalid handle")
+		return
+	}
+
+	// PUT replaces both credentials, so leaving one out is an error rather
+	// than a way to blank it. Like the password and email endpoints it
+	// needs the current password, and it signs out every other session.
+	// Partial updates go through PATCH /api/users/me.
+	if params.Email == "" || params.Password == "" {
+		respondWithError(w, 400, "Email and password are required")
+		return
+	}
+
+	if !cfg.reauthenticate(w, req, userID, params.CurrentPassword) {
+		return
+	}
+
 	HashedPassword, err := auth.HashPassword(params.Password)
 	if err != nil {
 		log.Printf("failed to hash password: %s", err)
@@ -493,66 +1122,138 @@ func (cfg *apiConfig) handlerPutUsers(w http.ResponseWriter, req *http.Request)
 		HashedPassword: HashedPassword,
 	}
 
-	updatedUser, err := cfg.dbQueries.UpdateUser(req.Context(), myParams)
+	tx, err := cfg.db.BeginTx(req.Context(), nil)
 	if err != nil {
-		log.Printf("failed to update user: %s", err)
+		log.Printf("failed to begin transaction: %s", err)
 		respondWithError(w, 500, "Internal server error")
 		return
 	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
 
-	respBody := user{
-		ID:          updatedUser.ID,
-		CreatedAt:   updatedUser.CreatedAt,
-		UpdatedAt:   updatedUser.UpdatedAt,
-		Email:       updatedUser.Email,
-		IsChirpyRed: updatedUser.IsChirpyRed,
+	updatedUser, err := qtx.UpdateUser(req.Context(), myParams)
+	if err == nil && params.Handle != "" {
+		updatedUser, err = qtx.SetUserHandle(req.Context(), database.SetUserHandleParams{
+			ID:     userID,
+			Handle: sql.NullString{String: params.Handle, Valid: true},
+		})
+	}
+	if err == nil && params.IsPrivate != nil {
+		updatedUser, err = qtx.SetUserPrivacy(req.Context(), database.SetUserPrivacyParams{
+			ID:        userID,
+			IsPrivate: *params.IsPrivate,
+		})
+		// Going public lets everyone follow, so waiting requests are
+		// accepted along the way.
+		if err == nil && !*params.IsPrivate {
+			err = qtx.AcceptAllFollowRequests(req.Context(), userID)
+		}
+	}
+	if isUniqueViolation(err) {
+		log.Printf("failed to update user: %s", err)
+		respondWithError(w, 409, "Email or handle already taken")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to update user: %s", err)
+		respondWithDBError(w, err)
+		return
 	}
 
-	respondWithJSON(w, 200, respBody)
-}
+	// The caller gets a fresh refresh token in place of the one revoked
+	// along with every other session.
+	err = qtx.RevokeUserRefreshTokens(req.Context(), userID)
+	if err != nil {
+		log.Printf("failed to revoke refresh tokens: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
 
-func (cfg *apiConfig) handlerDeleteChirpsByID(w http.ResponseWriter, req *http.Request) {
-	tokenString, err := auth.GetBearerToken(req.Header)
+	token, refreshToken, err := cfg.createSession(req.Context(), qtx, userID)
 	if err != nil {
-		log.Printf("failed to get bearer token: %v", err)
-		respondWithError(w, 401, "Unauthorized")
+		log.Printf("failed to create session: %s", err)
+		respondWithDBError(w, err)
 		return
 	}
 
-	userID, err := auth.ValidateJWT(tokenString, cfg.secret)
+	err = tx.Commit()
 	if err != nil {
-		log.Printf("failed to validate token string: %v", err)
-		respondWithError(w, 401, "Unauthorized")
+		log.Printf("failed to commit user update: %s", err)
+		respondWithDBError(w, err)
 		return
 	}
 
+	respBody := struct {
+		user
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}{
+		user:         buildUser(updatedUser),
+		Token:        token,
+		RefreshToken: refreshToken,
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
+
+// ownedChirp authenticates the caller and loads the chirp named in the path,
+// which they must have written. It responds with an error itself when it
+// returns false.
+func (cfg *apiConfig) ownedChirp(w http.ResponseWriter, req *http.Request) (database.Chirp, bool) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return database.Chirp{}, false
+	}
+
 	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
 	if err != nil {
 		log.Printf("failed to parse chirpID string to uuid: %s", err)
 		respondWithError(w, 400, "Invalid chirp ID")
-		return
+		return database.Chirp{}, false
 	}
 
-	chirpByID, err := cfg.dbQueries.GetChirpByID(req.Context(), chirpID)
+	chirpByID, err := cfg.dbQueries.GetChirpByID(req.Context(), database.GetChirpByIDParams{
+		ID:       chirpID,
+		ViewerID: uuid.NullUUID{UUID: userID, Valid: true},
+	})
 	switch err {
 	case nil:
 	case sql.ErrNoRows:
 		log.Printf("failed to get chirp, Id not found: %s", err)
 		respondWithError(w, 404, "Chirp not found")
-		return
+		return database.Chirp{}, false
 	default:
 		log.Printf("failed to get chirp: %s", err)
 		respondWithError(w, 500, "Internal server error")
-		return
+		return database.Chirp{}, false
 	}
 
 	if userID != chirpByID.UserID {
 		log.Printf("Not owner of the chirp")
 		respondWithError(w, 403, "Unauthorized")
+		return database.Chirp{}, false
+	}
+
+	return chirpByID, true
+}
+
+func (cfg *apiConfig) handlerDeleteChirpsByID(w http.ResponseWriter, req *http.Request) {
+	chirpByID, ok := cfg.ownedChirp(w, req)
+	if !ok {
 		return
 	}
 
-	err = cfg.dbQueries.DeleteChirp(req.Context(), chirpID)
+	// A rechirp has nothing worth restoring and would keep holding the
+	// user's only rechirp of the original, so it is removed for good.
+	var err error
+	if chirpByID.RechirpOfID.Valid {
+		err = cfg.dbQueries.DeleteRechirp(req.Context(), database.DeleteRechirpParams{
+			UserID:      chirpByID.UserID,
+			RechirpOfID: chirpByID.RechirpOfID,
+		})
+	} else {
+		err = cfg.dbQueries.SoftDeleteChirp(req.Context(), chirpByID.ID)
+	}
 	if err != nil {
 		log.Printf("failed to delete chirp: %s", err)
 		respondWithError(w, 500, "Internal server error")
@@ -617,6 +1318,36 @@ func (cfg *apiConfig) handlerPostPolkaWebhooks(w http.ResponseWriter, req *http.
 	w.WriteHeader(204)
 }
 
+// durationFromEnv reads a duration such as "5m" from the environment,
+// falling back to the given default when the variable is unset.
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Fatalf("invalid duration for %s: %q", key, value)
+	}
+	return duration
+}
+
+// intFromEnv reads a non-negative integer from the environment, falling
+// back to the given default when the variable is unset.
+func intFromEnv(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || number < 0 {
+		log.Fatalf("invalid integer for %s: %q", key, value)
+	}
+	return int(number)
+}
+
 // MAIN
 
 func main() {
@@ -635,18 +1366,63 @@ func main() {
 	dbQueries := database.New(db)
 
 	apiCfg := apiConfig{}
+	apiCfg.db = db
 	apiCfg.dbQueries = dbQueries
 	apiCfg.platform = os.Getenv("PLATFORM")
 	apiCfg.secret = os.Getenv("secret")
 	apiCfg.polkaKey = os.Getenv("POLKA_KEY")
+	apiCfg.adminKey = os.Getenv("ADMIN_KEY")
+	apiCfg.duplicateWindow = durationFromEnv("DUPLICATE_CHIRP_WINDOW", 10*time.Minute)
+	apiCfg.restoreWindow = durationFromEnv("RESTORE_WINDOW", 30*24*time.Hour)
+	apiCfg.fanOutLimit = int32(intFromEnv("TIMELINE_FANOUT_LIMIT", 10000))
+
+	apiCfg.blobStore, err = newBlobStore()
+	if err != nil {
+		log.Fatalln("failed to create blob store: %w", err)
+	}
+
+	apiCfg.contentFilter, err = newContentFilter(context.Background(), dbQueries)
+	if err != nil {
+		log.Fatalln("failed to create content filter: %w", err)
+	}
+
+	messageKey, err := msgcrypt.ParseKey(os.Getenv("MESSAGE_KEY"))
+	if err != nil {
+		log.Fatalln("failed to read MESSAGE_KEY: %w", err)
+	}
+	apiCfg.messageCipher, err = msgcrypt.NewCipher(messageKey)
+	if err != nil {
+		log.Fatalln("failed to create message cipher: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var workers sync.WaitGroup
+	startWorker := func(name string, interval time.Duration, task func(context.Context) error) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			runPeriodically(ctx, name, interval, task)
+		}()
+	}
+	startWorker("trending", durationFromEnv("TRENDING_REFRESH_INTERVAL", 5*time.Minute), apiCfg.refreshTrending)
+	startWorker("polls", durationFromEnv("POLL_FINALIZE_INTERVAL", time.Minute), apiCfg.finalizePolls)
+	startWorker("idempotency", durationFromEnv("IDEMPOTENCY_PURGE_INTERVAL", time.Hour), apiCfg.purgeIdempotencyKeys)
+	startWorker("purge", durationFromEnv("PURGE_INTERVAL", time.Hour), apiCfg.purgeDeleted)
+	startWorker("reaper", durationFromEnv("REAPER_INTERVAL", time.Minute), apiCfg.reapExpiredChirps)
+	startWorker("timeline", durationFromEnv("TIMELINE_BACKFILL_INTERVAL", 10*time.Second), apiCfg.backfillTimelines)
+	startWorker("scheduler", durationFromEnv("SCHEDULER_INTERVAL", 15*time.Second), apiCfg.publishScheduledChirps)
 
 	mux := http.NewServeMux()
 	mux.Handle("/app/", apiCfg.middlewareMetricsInc(http.StripPrefix("/app", http.FileServer(http.Dir(".")))))
 	mux.HandleFunc("GET /admin/metrics", apiCfg.handlerGetMetrics)
 	mux.HandleFunc("POST /admin/reset", apiCfg.handlerPostReset)
+	mux.HandleFunc("POST /admin/content-filter/reload", apiCfg.handlerPostContentFilterReload)
+	mux.HandleFunc("GET /admin/flagged-chirps", apiCfg.handlerGetFlaggedChirps)
 	mux.HandleFunc("GET /api/healthz", handlerGetHealthz)
-	mux.HandleFunc("POST /api/users", apiCfg.handlerPostUser)
-	mux.HandleFunc("POST /api/chirps", apiCfg.handlerPostChirp)
+	mux.HandleFunc("POST /api/users", apiCfg.idempotent(apiCfg.handlerPostUser))
+	mux.HandleFunc("POST /api/chirps", apiCfg.idempotent(apiCfg.handlerPostChirp))
 	mux.HandleFunc("GET /api/chirps", apiCfg.handlerGetChirps)
 	mux.HandleFunc("GET /api/chirps/{chirpID}", apiCfg.handlerGetChirpsByID)
 	mux.HandleFunc("POST /api/login", apiCfg.handlerPostLogin)
@@ -654,16 +1430,94 @@ func main() {
 	mux.HandleFunc("POST /api/revoke", apiCfg.handlerPostRevoke)
 	mux.HandleFunc("PUT /api/users", apiCfg.handlerPutUsers)
 	mux.HandleFunc("DELETE /api/chirps/{chirpID}", apiCfg.handlerDeleteChirpsByID)
-	mux.HandleFunc("POST /api/polka/webhooks", apiCfg.handlerPostPolkaWebhooks)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/restore", apiCfg.handlerPostChirpRestore)
+	mux.HandleFunc("DELETE /api/users", apiCfg.handlerDeleteUsers)
+	mux.HandleFunc("POST /api/users/restore", apiCfg.handlerPostUsersRestore)
+	mux.HandleFunc("GET /api/users/{handle}", apiCfg.handlerGetUserProfile)
+	mux.HandleFunc("PATCH /api/users/me", apiCfg.handlerPatchUsersMe)
+	mux.HandleFunc("POST /api/users/me/password", apiCfg.handlerPostUsersMePassword)
+	mux.HandleFunc("POST /api/users/me/email", apiCfg.handlerPostUsersMeEmail)
+	mux.HandleFunc("POST /api/users/me/avatar", apiCfg.handlerPostUsersMeAvatar)
+	mux.HandleFunc("DELETE /api/users/me/avatar", apiCfg.handlerDeleteUsersMeAvatar)
+	mux.HandleFunc("POST /api/users/{userID}/follow", apiCfg.handlerPostFollow)
+	mux.HandleFunc("DELETE /api/users/{userID}/follow", apiCfg.handlerDeleteFollow)
+	mux.HandleFunc("GET /api/users/{userID}/followers", apiCfg.handlerGetFollowers)
+	mux.HandleFunc("GET /api/users/{userID}/following", apiCfg.handlerGetFollowing)
+	mux.HandleFunc("POST /api/users/{userID}/block", apiCfg.handlerPostBlock)
+	mux.HandleFunc("DELETE /api/users/{userID}/block", apiCfg.handlerDeleteBlock)
+	mux.HandleFunc("POST /api/users/{userID}/mute", apiCfg.handlerPostMute)
+	mux.HandleFunc("DELETE /api/users/{userID}/mute", apiCfg.handlerDeleteMute)
+	mux.HandleFunc("GET /api/follow-requests", apiCfg.handlerGetFollowRequests)
+	mux.HandleFunc("POST /api/follow-requests/{userID}/accept", apiCfg.handlerPostFollowRequestAccept)
+	mux.HandleFunc("DELETE /api/follow-requests/{userID}", apiCfg.handlerDeleteFollowRequest)
+	mux.HandleFunc("POST /api/polka/webhooks", apiCfg.idempotent(apiCfg.handlerPostPolkaWebhooks))
+	mux.HandleFunc("POST /api/chirps/{chirpID}/likes", apiCfg.handlerPostChirpLikes)
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/likes", apiCfg.handlerDeleteChirpLikes)
+	mux.HandleFunc("GET /api/chirps/{chirpID}/likes", apiCfg.handlerGetChirpLikes)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/rechirp", apiCfg.handlerPostRechirp)
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/rechirp", apiCfg.handlerDeleteRechirp)
+	mux.HandleFunc("GET /api/hashtags/{tag}/chirps", apiCfg.handlerGetHashtagChirps)
+	mux.HandleFunc("GET /api/mentions", apiCfg.handlerGetMentions)
+	mux.HandleFunc("GET /api/trending", apiCfg.handlerGetTrending)
+	mux.HandleFunc("GET /api/timeline", apiCfg.handlerGetTimeline)
+	mux.HandleFunc("GET /api/notifications", apiCfg.handlerGetNotifications)
+	mux.HandleFunc("POST /api/notifications/read", apiCfg.handlerPostNotificationsRead)
+	mux.HandleFunc("GET /api/notifications/preferences", apiCfg.handlerGetNotificationPreferences)
+	mux.HandleFunc("PUT /api/notifications/preferences", apiCfg.handlerPutNotificationPreferences)
+	mux.HandleFunc("POST /api/conversations", apiCfg.handlerPostConversations)
+	mux.HandleFunc("GET /api/conversations", apiCfg.handlerGetConversations)
+	mux.HandleFunc("GET /api/conversations/{conversationID}/messages", apiCfg.handlerGetConversationMessages)
+	mux.HandleFunc("POST /api/conversations/{conversationID}/messages", apiCfg.handlerPostConversationMessages)
+	mux.HandleFunc("POST /api/lists", apiCfg.handlerPostLists)
+	mux.HandleFunc("GET /api/lists", apiCfg.handlerGetLists)
+	mux.HandleFunc("GET /api/lists/{listID}", apiCfg.handlerGetListsByID)
+	mux.HandleFunc("PUT /api/lists/{listID}", apiCfg.handlerPutListsByID)
+	mux.HandleFunc("DELETE /api/lists/{listID}", apiCfg.handlerDeleteListsByID)
+	mux.HandleFunc("GET /api/lists/{listID}/members", apiCfg.handlerGetListMembers)
+	mux.HandleFunc("PUT /api/lists/{listID}/members/{userID}", apiCfg.handlerPutListMember)
+	mux.HandleFunc("DELETE /api/lists/{listID}/members/{userID}", apiCfg.handlerDeleteListMember)
+	mux.HandleFunc("GET /api/lists/{listID}/chirps", apiCfg.handlerGetListChirps)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/bookmark", apiCfg.handlerPostBookmark)
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/bookmark", apiCfg.handlerDeleteBookmark)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/pin", apiCfg.handlerPostChirpPin)
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/pin", apiCfg.handlerDeleteChirpPin)
+	mux.HandleFunc("GET /api/bookmarks", apiCfg.handlerGetBookmarks)
+	mux.HandleFunc("POST /api/bookmarks/folders", apiCfg.handlerPostBookmarkFolders)
+	mux.HandleFunc("GET /api/bookmarks/folders", apiCfg.handlerGetBookmarkFolders)
+	mux.HandleFunc("DELETE /api/bookmarks/folders/{folderID}", apiCfg.handlerDeleteBookmarkFolder)
+	mux.HandleFunc("POST /api/media", apiCfg.handlerPostMedia)
+	mux.HandleFunc("GET /api/media/{key}", apiCfg.handlerGetMedia)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/poll/votes", apiCfg.handlerPostPollVotes)
+	mux.HandleFunc("POST /api/drafts", apiCfg.handlerPostDrafts)
+	mux.HandleFunc("GET /api/drafts", apiCfg.handlerGetDrafts)
+	mux.HandleFunc("GET /api/drafts/{draftID}", apiCfg.handlerGetDraftsByID)
+	mux.HandleFunc("PUT /api/drafts/{draftID}", apiCfg.handlerPutDraftsByID)
+	mux.HandleFunc("DELETE /api/drafts/{draftID}", apiCfg.handlerDeleteDraftsByID)
 
 	svr := &http.Server{
 		Handler: mux,
 		Addr:    ":8080",
 	}
 
-	err = svr.ListenAndServe()
-	if err != nil {
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- svr.ListenAndServe()
+	}()
+
+	select {
+	case err = <-serverErr:
 		log.Fatalln("failed to listen and serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	log.Print("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = svr.Shutdown(shutdownCtx)
+	if err != nil {
+		log.Printf("failed to shut down server: %s", err)
 	}
 
+	workers.Wait()
 }
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/blobstore"
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/LouisRemes-95/chirpy.git/internal/media"
+	"github.com/google/uuid"
+)
+
+const (
+	maxUploadSize     = 10 << 20
+	maxMediaPerChirp  = 4
+	mediaCacheControl = "public, max-age=31536000, immutable"
+)
+
+var (
+	errInvalidMedia = errors.New("invalid media")
+	mediaKeyRegex   = regexp.MustCompile(`^[0-9a-f]{64}(_thumb)?\.(jpg|png)$`)
+)
+
+type mediaAttachment struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ContentType  string    `json:"content_type"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+}
+
+// newBlobStore builds the blob store selected by MEDIA_STORE: "local"
+// (the default) writes to MEDIA_DIR, "s3" talks to an S3-compatible service.
+func newBlobStore() (blobstore.BlobStore, error) {
+	switch os.Getenv("MEDIA_STORE") {
+	case "", "local":
+		dir := os.Getenv("MEDIA_DIR")
+		if dir == "" {
+			dir = "media"
+		}
+		return blobstore.NewLocalStore(dir)
+	case "s3":
+		return blobstore.NewS3Store(
+			os.Getenv("S3_ENDPOINT"),
+			os.Getenv("S3_BUCKET"),
+			os.Getenv("S3_REGION"),
+			os.Getenv("S3_ACCESS_KEY_ID"),
+			os.Getenv("S3_SECRET_ACCESS_KEY"),
+		)
+	default:
+		return nil, fmt.Errorf("unknown MEDIA_STORE %q", os.Getenv("MEDIA_STORE"))
+	}
+}
+
+func buildMediaAttachment(item database.MediaItem) mediaAttachment {
+	return mediaAttachment{
+		ID:           item.ID,
+		URL:          "/api/media/" + item.BlobKey,
+		ThumbnailURL: "/api/media/" + item.ThumbnailKey,
+		ContentType:  item.ContentType,
+		Width:        item.Width,
+		Height:       item.Height,
+	}
+}
+
+// attachMedia links uploaded media to a new chirp, in the given order. Media
+// must belong to the author and not already be attached to another chirp.
+func attachMedia(ctx context.Context, q *database.Queries, createdChirp database.Chirp, mediaIDs []uuid.UUID) error {
+	for i, mediaID := range mediaIDs {
+		attached, err := q.AttachMediaItem(ctx, database.AttachMediaItemParams{
+			ChirpID:  uuid.NullUUID{UUID: createdChirp.ID, Valid: true},
+			Position: int32(i),
+			ID:       mediaID,
+			UserID:   createdChirp.UserID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to attach media: %w", err)
+		}
+		if attached == 0 {
+			return fmt.Errorf("%w: %s", errInvalidMedia, mediaID)
+		}
+	}
+	return nil
+}
+
+// imageKey names an image after its content, so the same bytes always get
+// the same URL and can be cached forever.
+func imageKey(img media.Image, suffix string) string {
+	sum := sha256.Sum256(img.Data)
+	return hex.EncodeToString(sum[:]) + suffix + img.Extension
+}
+
+func (cfg *apiConfig) handlerPostMedia(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	item, ok := cfg.uploadImage(w, req, userID)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, 201, buildMediaAttachment(item))
+}
+
+// uploadImage reads the image in the "file" field of a multipart request,
+// stores it with its thumbnail and records it as a media item of the user. It
+// responds with an error itself when it returns false.
+func (cfg *apiConfig) uploadImage(w http.ResponseWriter, req *http.Request, userID uuid.UUID) (database.MediaItem, bool) {
+	req.Body = http.MaxBytesReader(w, req.Body, maxUploadSize+(1<<20))
+	file, _, err := req.FormFile("file")
+	if err != nil {
+		log.Printf("failed to read uploaded file: %s", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondWithError(w, 413, "File too large")
+			return database.MediaItem{}, false
+		}
+		respondWithError(w, 400, "Missing file")
+		return database.MediaItem{}, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxUploadSize+1))
+	if err != nil {
+		log.Printf("failed to read uploaded file: %s", err)
+		respondWithError(w, 400, "Invalid file")
+		return database.MediaItem{}, false
+	}
+	if len(data) > maxUploadSize {
+		respondWithError(w, 413, "File too large")
+		return database.MediaItem{}, false
+	}
+
+	processed, err := media.Process(data)
+	switch {
+	case err == nil:
+	case errors.Is(err, media.ErrUnsupportedType):
+		respondWithError(w, 415, "Unsupported media type")
+		return database.MediaItem{}, false
+	case errors.Is(err, media.ErrTooLarge):
+		respondWithError(w, 413, "Image dimensions too large")
+		return database.MediaItem{}, false
+	default:
+		log.Printf("failed to process image: %s", err)
+		respondWithError(w, 400, "Invalid image")
+		return database.MediaItem{}, false
+	}
+
+	blobKey := imageKey(processed.Original, "")
+	thumbnailKey := imageKey(processed.Thumbnail, "_thumb")
+
+	// The media item is written before the blobs and in the same transaction.
+	// Its insert waits for a blob deletion of the same content that is under
+	// way, so the blobs are stored again once it is done.
+	tx, err := cfg.db.BeginTx(req.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return database.MediaItem{}, false
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	item, err := qtx.CreateMediaItem(req.Context(), database.CreateMediaItemParams{
+		UserID:       userID,
+		ContentType:  processed.Original.ContentType,
+		BlobKey:      blobKey,
+		ThumbnailKey: thumbnailKey,
+		Width:        int32(processed.Original.Width),
+		Height:       int32(processed.Original.Height),
+		SizeBytes:    int64(len(processed.Original.Data)),
+	})
+	if err != nil {
+		log.Printf("failed to create media item: %s", err)
+		respondWithDBError(w, err)
+		return database.MediaItem{}, false
+	}
+
+	err = cfg.blobStore.Put(req.Context(), blobKey, processed.Original.ContentType, processed.Original.Data)
+	if err != nil {
+		log.Printf("failed to store image: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return database.MediaItem{}, false
+	}
+
+	err = cfg.blobStore.Put(req.Context(), thumbnailKey, processed.Thumbnail.ContentType, processed.Thumbnail.Data)
+	if err != nil {
+		log.Printf("failed to store thumbnail: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return database.MediaItem{}, false
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("failed to commit media item: %s", err)
+		respondWithDBError(w, err)
+		return database.MediaItem{}, false
+	}
+
+	return item, true
+}
+
+// mediaCacheControlFor returns the Cache-Control header for a media key. Media
+// of an expiring chirp may only be cached until the chirp expires. Media that
+// only some viewers may see must not be stored by shared caches, and is
+// revalidated so it stops being served once the viewer loses access.
+func (cfg *apiConfig) mediaCacheControlFor(ctx context.Context, key string, public bool) (string, error) {
+	if !public {
+		return "private, no-cache", nil
+	}
+	expiresAt, err := cfg.dbQueries.GetMediaExpiry(ctx, key)
+	if err == sql.ErrNoRows {
+		return mediaCacheControl, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	maxAge := max(int(time.Until(expiresAt.Time).Seconds()), 0)
+	return fmt.Sprintf("public, max-age=%d", maxAge), nil
+}
+
+// handlerGetMedia serves a media blob to viewers who may see at least one
+// chirp it is attached to. Media not attached to any chirp, such as avatars,
+// is public.
+func (cfg *apiConfig) handlerGetMedia(w http.ResponseWriter, req *http.Request) {
+	key := req.PathValue("key")
+	if !mediaKeyRegex.MatchString(key) {
+		respondWithError(w, 404, "Media not found")
+		return
+	}
+
+	access, err := cfg.dbQueries.GetMediaAccess(req.Context(), database.GetMediaAccessParams{
+		BlobKey:  key,
+		ViewerID: cfg.viewerID(req),
+	})
+	if err != nil {
+		log.Printf("failed to get media access: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	if !access.Visible {
+		respondWithError(w, 404, "Media not found")
+		return
+	}
+
+	cacheControl, err := cfg.mediaCacheControlFor(req.Context(), key, access.Public)
+	if err != nil {
+		log.Printf("failed to get media expiry: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	// Keys are content hashes, so a matching ETag always means an identical file.
+	etag := `"` + key + `"`
+	if req.Header.Get("If-None-Match") == etag {
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", cacheControl)
+		w.WriteHeader(304)
+		return
+	}
+
+	blob, err := cfg.blobStore.Get(req.Context(), key)
+	switch {
+	case err == nil:
+	case errors.Is(err, blobstore.ErrNotFound):
+		respondWithError(w, 404, "Media not found")
+		return
+	default:
+		log.Printf("failed to get media: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	defer blob.Body.Close()
+
+	w.Header().Set("Content-Type", blob.ContentType)
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("ETag", etag)
+	if blob.Size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(blob.Size, 10))
+	}
+	w.WriteHeader(200)
+
+	_, err = io.Copy(w, blob.Body)
+	if err != nil {
+		log.Printf("failed to write media: %s", err)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/chirptext"
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+// saveMentions resolves the @handles of a newly created chirp to users and
+// records them. Handles that don't belong to anyone, or to someone blocking
+// the author, are left as plain text.
+func saveMentions(ctx context.Context, q *database.Queries, createdChirp database.Chirp) error {
+	handles := chirptext.Mentions(createdChirp.Body)
+	if len(handles) == 0 {
+		return nil
+	}
+
+	mentionedUsers, err := q.GetUsersByHandles(ctx, database.GetUsersByHandlesParams{
+		Handles:  handles,
+		AuthorID: createdChirp.UserID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve handles: %w", err)
+	}
+
+	for _, mentionedUser := range mentionedUsers {
+		err = q.CreateMention(ctx, database.CreateMentionParams{
+			ChirpID: createdChirp.ID,
+			UserID:  mentionedUser.ID,
+			Handle:  strings.ToLower(mentionedUser.Handle.String),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create mention: %w", err)
+		}
+
+		err = notify(ctx, q, notificationMention, mentionedUser.ID, createdChirp.UserID, uuid.NullUUID{UUID: createdChirp.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cfg *apiConfig) handlerGetMentions(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(req)
+	if err != nil {
+		log.Printf("failed to parse pagination: %s", err)
+		respondWithError(w, 400, "Invalid pagination")
+		return
+	}
+
+	newestFirst, err := parseSortOrder(req)
+	if err != nil {
+		log.Printf("failed to parse sort order: %s", err)
+		respondWithError(w, 400, "Invalid sort")
+		return
+	}
+
+	chirps, err := cfg.dbQueries.GetChirpsMentioningUser(req.Context(), database.GetChirpsMentioningUserParams{
+		UserID:      userID,
+		NewestFirst: newestFirst,
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		log.Printf("failed to get mentions: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody, err := cfg.buildChirps(req.Context(), chirps, uuid.NullUUID{UUID: userID, Valid: true})
+	if err != nil {
+		log.Printf("failed to build chirps response: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
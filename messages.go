@@ -0,0 +1,525 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/chirptext"
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxConversationParticipants includes the user starting the
+	// conversation.
+	maxConversationParticipants = 10
+	maxMessageLength            = 1000
+)
+
+type conversationParticipant struct {
+	UserID         uuid.UUID `json:"user_id"`
+	Handle         string    `json:"handle,omitempty"`
+	LastReadCursor string    `json:"last_read_cursor,omitempty"`
+}
+
+type conversation struct {
+	ID            uuid.UUID                 `json:"id"`
+	IsGroup       bool                      `json:"is_group"`
+	Participants  []conversationParticipant `json:"participants"`
+	UnreadCount   int64                     `json:"unread_count"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	LastMessageAt *time.Time                `json:"last_message_at,omitempty"`
+}
+
+type message struct {
+	ID        uuid.UUID   `json:"id"`
+	Cursor    string      `json:"cursor"`
+	SenderID  uuid.UUID   `json:"sender_id"`
+	Body      string      `json:"body"`
+	CreatedAt time.Time   `json:"created_at"`
+	ReadBy    []uuid.UUID `json:"read_by"`
+}
+
+// directKey identifies the one-to-one conversation between two users
+// whichever of them starts it.
+func directKey(first, second uuid.UUID) string {
+	keys := []string{first.String(), second.String()}
+	slices.Sort(keys)
+	return strings.Join(keys, ":")
+}
+
+// messageRefusal explains why the sender may not message a recipient, or is
+// empty if they may.
+func messageRefusal(recipient database.GetMessageRecipientsRow) string {
+	switch {
+	case recipient.Blocked:
+		return "You can't message this user"
+	case recipient.DmsFromFollowingOnly && !recipient.FollowsSender:
+		return "This user only accepts messages from people they follow"
+	}
+	return ""
+}
+
+func participantCursor(lastReadSeq int64) string {
+	if lastReadSeq == 0 {
+		return ""
+	}
+	return strconv.FormatInt(lastReadSeq, 10)
+}
+
+// buildConversations lists the participants of each conversation and counts
+// the messages the user hasn't read yet.
+func (cfg *apiConfig) buildConversations(ctx context.Context, conversations []database.Conversation, userID uuid.UUID) ([]conversation, error) {
+	conversationIDs := make([]uuid.UUID, len(conversations))
+	for i, currentConversation := range conversations {
+		conversationIDs[i] = currentConversation.ID
+	}
+
+	participants, err := cfg.dbQueries.GetConversationParticipants(ctx, conversationIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participants: %w", err)
+	}
+	participantsByConversation := map[uuid.UUID][]conversationParticipant{}
+	for _, participant := range participants {
+		participantsByConversation[participant.ConversationID] = append(participantsByConversation[participant.ConversationID], conversationParticipant{
+			UserID:         participant.ID,
+			Handle:         participant.Handle.String,
+			LastReadCursor: participantCursor(participant.LastReadSeq),
+		})
+	}
+
+	unreadCounts, err := cfg.dbQueries.CountUnreadMessages(ctx, database.CountUnreadMessagesParams{
+		UserID:          userID,
+		ConversationIds: conversationIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread messages: %w", err)
+	}
+	unreadByConversation := map[uuid.UUID]int64{}
+	for _, unread := range unreadCounts {
+		unreadByConversation[unread.ConversationID] = unread.UnreadCount
+	}
+
+	respBody := make([]conversation, len(conversations))
+	for i, currentConversation := range conversations {
+		respBody[i] = conversation{
+			ID:           currentConversation.ID,
+			IsGroup:      !currentConversation.DirectKey.Valid,
+			Participants: participantsByConversation[currentConversation.ID],
+			UnreadCount:  unreadByConversation[currentConversation.ID],
+			CreatedAt:    currentConversation.CreatedAt,
+		}
+		if currentConversation.LastMessageAt.Valid {
+			respBody[i].LastMessageAt = &currentConversation.LastMessageAt.Time
+		}
+	}
+	return respBody, nil
+}
+
+// conversationForUser authenticates the caller and loads the conversation
+// named in the path, which they must take part in. It responds with an error
+// itself when it returns false.
+func (cfg *apiConfig) conversationForUser(w http.ResponseWriter, req *http.Request) (database.Conversation, uuid.UUID, bool) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return database.Conversation{}, uuid.UUID{}, false
+	}
+
+	conversationID, err := uuid.Parse(req.PathValue("conversationID"))
+	if err != nil {
+		log.Printf("failed to parse conversationID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid conversation ID")
+		return database.Conversation{}, uuid.UUID{}, false
+	}
+
+	currentConversation, err := cfg.dbQueries.GetConversationForUser(req.Context(), database.GetConversationForUserParams{
+		ID:     conversationID,
+		UserID: userID,
+	})
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to get conversation, Id not found: %s", err)
+		respondWithError(w, 404, "Conversation not found")
+		return database.Conversation{}, uuid.UUID{}, false
+	default:
+		log.Printf("failed to get conversation: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return database.Conversation{}, uuid.UUID{}, false
+	}
+
+	return currentConversation, userID, true
+}
+
+func (cfg *apiConfig) handlerPostConversations(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := struct {
+		ParticipantIDs []uuid.UUID `json:"participant_ids"`
+	}{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		log.Printf("failed to decode parameters: %s", err)
+		respondWithError(w, 400, "Invalid request body")
+		return
+	}
+
+	otherIDs := []uuid.UUID{}
+	for _, participantID := range params.ParticipantIDs {
+		if participantID != userID && !slices.Contains(otherIDs, participantID) {
+			otherIDs = append(otherIDs, participantID)
+		}
+	}
+	if len(otherIDs) == 0 {
+		respondWithError(w, 400, "A conversation needs at least one other participant")
+		return
+	}
+	if len(otherIDs)+1 > maxConversationParticipants {
+		respondWithError(w, 400, fmt.Sprintf("A conversation can have at most %d participants", maxConversationParticipants))
+		return
+	}
+
+	recipients, err := cfg.dbQueries.GetMessageRecipients(req.Context(), database.GetMessageRecipientsParams{
+		SenderID: userID,
+		UserIds:  otherIDs,
+	})
+	if err != nil {
+		log.Printf("failed to get recipients: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	if len(recipients) != len(otherIDs) {
+		respondWithError(w, 404, "User not found")
+		return
+	}
+	for _, recipient := range recipients {
+		if refusal := messageRefusal(recipient); refusal != "" {
+			respondWithError(w, 403, refusal)
+			return
+		}
+	}
+
+	var key sql.NullString
+	if len(otherIDs) == 1 {
+		key = sql.NullString{String: directKey(userID, otherIDs[0]), Valid: true}
+	}
+
+	tx, err := cfg.db.BeginTx(req.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	// Starting a one-to-one conversation again returns the existing one.
+	status := 201
+	createdConversation, err := qtx.CreateConversation(req.Context(), key)
+	if err == sql.ErrNoRows {
+		status = 200
+		createdConversation, err = qtx.GetConversationByDirectKey(req.Context(), key)
+	} else if err == nil {
+		for _, participantID := range append([]uuid.UUID{userID}, otherIDs...) {
+			err = qtx.AddConversationParticipant(req.Context(), database.AddConversationParticipantParams{
+				ConversationID: createdConversation.ID,
+				UserID:         participantID,
+			})
+			if err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		log.Printf("failed to create conversation: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("failed to commit conversation: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	respBody, err := cfg.buildConversations(req.Context(), []database.Conversation{createdConversation}, userID)
+	if err != nil {
+		log.Printf("failed to build conversation response: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respondWithJSON(w, status, respBody[0])
+}
+
+func (cfg *apiConfig) handlerGetConversations(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(req)
+	if err != nil {
+		log.Printf("failed to parse pagination: %s", err)
+		respondWithError(w, 400, "Invalid pagination")
+		return
+	}
+
+	conversations, err := cfg.dbQueries.GetConversationsByUserID(req.Context(), database.GetConversationsByUserIDParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		log.Printf("failed to get conversations: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody, err := cfg.buildConversations(req.Context(), conversations, userID)
+	if err != nil {
+		log.Printf("failed to build conversations response: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
+
+func (cfg *apiConfig) handlerGetConversationMessages(w http.ResponseWriter, req *http.Request) {
+	currentConversation, userID, ok := cfg.conversationForUser(w, req)
+	if !ok {
+		return
+	}
+
+	limit, err := parseLimit(req)
+	if err != nil {
+		log.Printf("failed to parse pagination: %s", err)
+		respondWithError(w, 400, "Invalid pagination")
+		return
+	}
+
+	params := database.GetMessagesParams{
+		ConversationID: currentConversation.ID,
+		ViewerID:       userID,
+		Limit:          limit,
+	}
+	if cursor := req.URL.Query().Get("cursor"); cursor != "" {
+		seq, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			log.Printf("failed to parse cursor: %s", err)
+			respondWithError(w, 400, "Invalid cursor")
+			return
+		}
+		params.BeforeSeq = sql.NullInt64{Int64: seq, Valid: true}
+	}
+
+	messages, err := cfg.dbQueries.GetMessages(req.Context(), params)
+	if err != nil {
+		log.Printf("failed to get messages: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	// Reading the latest page of a conversation marks it as read.
+	if !params.BeforeSeq.Valid && len(messages) > 0 {
+		err = cfg.dbQueries.MarkConversationRead(req.Context(), database.MarkConversationReadParams{
+			Seq:            messages[0].Seq,
+			ConversationID: currentConversation.ID,
+			UserID:         userID,
+		})
+		if err != nil {
+			log.Printf("failed to mark conversation as read: %s", err)
+			respondWithError(w, 500, "Internal server error")
+			return
+		}
+	}
+
+	participants, err := cfg.dbQueries.GetConversationParticipants(req.Context(), []uuid.UUID{currentConversation.ID})
+	if err != nil {
+		log.Printf("failed to get participants: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := struct {
+		Messages     []message                 `json:"messages"`
+		Participants []conversationParticipant `json:"participants"`
+		NextCursor   string                    `json:"next_cursor,omitempty"`
+	}{
+		Messages:     make([]message, len(messages)),
+		Participants: make([]conversationParticipant, len(participants)),
+	}
+	for i, participant := range participants {
+		respBody.Participants[i] = conversationParticipant{
+			UserID:         participant.ID,
+			Handle:         participant.Handle.String,
+			LastReadCursor: participantCursor(participant.LastReadSeq),
+		}
+	}
+	for i, currentMessage := range messages {
+		body, err := cfg.messageCipher.Open(currentMessage.Body, currentConversation.ID[:])
+		if err != nil {
+			log.Printf("failed to decrypt message %s: %s", currentMessage.ID, err)
+			respondWithError(w, 500, "Internal server error")
+			return
+		}
+
+		respBody.Messages[i] = message{
+			ID:        currentMessage.ID,
+			Cursor:    strconv.FormatInt(currentMessage.Seq, 10),
+			SenderID:  currentMessage.SenderID,
+			Body:      string(body),
+			CreatedAt: currentMessage.CreatedAt,
+			ReadBy:    []uuid.UUID{},
+		}
+		for _, participant := range participants {
+			if participant.ID != currentMessage.SenderID && participant.LastReadSeq >= currentMessage.Seq {
+				respBody.Messages[i].ReadBy = append(respBody.Messages[i].ReadBy, participant.ID)
+			}
+		}
+	}
+	if len(messages) == int(limit) {
+		respBody.NextCursor = respBody.Messages[len(messages)-1].Cursor
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
+
+func (cfg *apiConfig) handlerPostConversationMessages(w http.ResponseWriter, req *http.Request) {
+	currentConversation, userID, ok := cfg.conversationForUser(w, req)
+	if !ok {
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := struct {
+		Body string `json:"body"`
+	}{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		log.Printf("failed to decode parameters: %s", err)
+		respondWithError(w, 400, "Invalid request body")
+		return
+	}
+
+	body, err := chirptext.ValidateTextField(params.Body, maxMessageLength, true)
+	switch {
+	case err == chirptext.ErrFieldTooLong:
+		respondWithError(w, 400, fmt.Sprintf("Message is too long, the limit is %d characters", maxMessageLength))
+		return
+	case err != nil:
+		respondWithError(w, 400, "Message contains control characters")
+		return
+	case body == "":
+		respondWithError(w, 400, "Message can't be empty")
+		return
+	}
+
+	// Blocks and message settings keep applying to one-to-one conversations
+	// after they start. In groups, blocked users only stop seeing each
+	// other's messages.
+	if currentConversation.DirectKey.Valid {
+		participants, err := cfg.dbQueries.GetConversationParticipants(req.Context(), []uuid.UUID{currentConversation.ID})
+		if err != nil {
+			log.Printf("failed to get participants: %s", err)
+			respondWithError(w, 500, "Internal server error")
+			return
+		}
+		otherIDs := []uuid.UUID{}
+		for _, participant := range participants {
+			if participant.ID != userID {
+				otherIDs = append(otherIDs, participant.ID)
+			}
+		}
+
+		recipients, err := cfg.dbQueries.GetMessageRecipients(req.Context(), database.GetMessageRecipientsParams{
+			SenderID: userID,
+			UserIds:  otherIDs,
+		})
+		if err != nil {
+			log.Printf("failed to get recipients: %s", err)
+			respondWithError(w, 500, "Internal server error")
+			return
+		}
+		if len(recipients) == 0 {
+			respondWithError(w, 403, "You can't message this user")
+			return
+		}
+		for _, recipient := range recipients {
+			if refusal := messageRefusal(recipient); refusal != "" {
+				respondWithError(w, 403, refusal)
+				return
+			}
+		}
+	}
+
+	// The conversation ID is bound to the ciphertext so that a message can't
+	// be moved to another conversation in the database.
+	sealed, err := cfg.messageCipher.Seal([]byte(body), currentConversation.ID[:])
+	if err != nil {
+		log.Printf("failed to encrypt message: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(req.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	createdMessage, err := qtx.CreateMessage(req.Context(), database.CreateMessageParams{
+		ConversationID: currentConversation.ID,
+		SenderID:       userID,
+		Body:           sealed,
+	})
+	if err != nil {
+		log.Printf("failed to create message: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	err = qtx.MarkConversationRead(req.Context(), database.MarkConversationReadParams{
+		Seq:            createdMessage.Seq,
+		ConversationID: currentConversation.ID,
+		UserID:         userID,
+	})
+	if err != nil {
+		log.Printf("failed to mark conversation as read: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("failed to commit message: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	respondWithJSON(w, 201, message{
+		ID:        createdMessage.ID,
+		Cursor:    strconv.FormatInt(createdMessage.Seq, 10),
+		SenderID:  createdMessage.SenderID,
+		Body:      body,
+		CreatedAt: createdMessage.CreatedAt,
+		ReadBy:    []uuid.UUID{},
+	})
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+// Chirpy has no replies, so quotes stand in for them: a quote is the only
+// way to respond to a chirp with one of your own.
+const (
+	notificationFollow        = "follow"
+	notificationFollowRequest = "follow_request"
+	notificationLike          = "like"
+	notificationRechirp       = "rechirp"
+	notificationQuote         = "quote"
+	notificationMention       = "mention"
+)
+
+var notificationTypes = []string{
+	notificationFollow,
+	notificationFollowRequest,
+	notificationLike,
+	notificationRechirp,
+	notificationQuote,
+	notificationMention,
+}
+
+// maxNotificationActors is how many of the people behind a grouped
+// notification are listed. The rest only show up in actor_count.
+const maxNotificationActors = 3
+
+type notificationActor struct {
+	UserID uuid.UUID `json:"user_id"`
+	Handle string    `json:"handle,omitempty"`
+}
+
+type notification struct {
+	ID         uuid.UUID           `json:"id"`
+	Cursor     string              `json:"cursor"`
+	Type       string              `json:"type"`
+	ChirpID    *uuid.UUID          `json:"chirp_id,omitempty"`
+	Actors     []notificationActor `json:"actors"`
+	ActorCount int32               `json:"actor_count"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+	Read       bool                `json:"read"`
+}
+
+// notify tells a user that the actor did something involving them. It adds
+// the actor to the matching unread notification if there is one, so that
+// likes of the same chirp become a single "5 people liked your chirp".
+// Nothing is recorded for users acting on themselves, blocked or muted
+// actors, or types the user turned off.
+func notify(ctx context.Context, q *database.Queries, notificationType string, userID, actorID uuid.UUID, chirpID uuid.NullUUID) error {
+	notificationID, err := q.CreateNotification(ctx, database.CreateNotificationParams{
+		UserID:  userID,
+		Type:    notificationType,
+		ChirpID: chirpID,
+		ActorID: actorID,
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	err = q.AddNotificationActor(ctx, database.AddNotificationActorParams{
+		NotificationID: notificationID,
+		ActorID:        actorID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add notification actor: %w", err)
+	}
+	return nil
+}
+
+func (cfg *apiConfig) handlerGetNotifications(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	limit, err := parseLimit(req)
+	if err != nil {
+		log.Printf("failed to parse pagination: %s", err)
+		respondWithError(w, 400, "Invalid pagination")
+		return
+	}
+
+	params := database.GetNotificationsParams{
+		UserID: userID,
+		Limit:  limit,
+	}
+	if cursor := req.URL.Query().Get("cursor"); cursor != "" {
+		seq, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			log.Printf("failed to parse cursor: %s", err)
+			respondWithError(w, 400, "Invalid cursor")
+			return
+		}
+		params.BeforeSeq = sql.NullInt64{Int64: seq, Valid: true}
+	}
+
+	notifications, err := cfg.dbQueries.GetNotifications(req.Context(), params)
+	if err != nil {
+		log.Printf("failed to get notifications: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	notificationIDs := make([]uuid.UUID, len(notifications))
+	for i, currentNotification := range notifications {
+		notificationIDs[i] = currentNotification.ID
+	}
+
+	actors, err := cfg.dbQueries.GetNotificationActors(req.Context(), database.GetNotificationActorsParams{
+		NotificationIds: notificationIDs,
+		PerNotification: maxNotificationActors,
+	})
+	if err != nil {
+		log.Printf("failed to get notification actors: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	actorsByNotification := map[uuid.UUID][]notificationActor{}
+	for _, actor := range actors {
+		actorsByNotification[actor.NotificationID] = append(actorsByNotification[actor.NotificationID], notificationActor{
+			UserID: actor.ID,
+			Handle: actor.Handle.String,
+		})
+	}
+
+	unreadCount, err := cfg.dbQueries.CountUnreadNotifications(req.Context(), userID)
+	if err != nil {
+		log.Printf("failed to count unread notifications: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := struct {
+		UnreadCount   int64          `json:"unread_count"`
+		Notifications []notification `json:"notifications"`
+		NextCursor    string         `json:"next_cursor,omitempty"`
+	}{
+		UnreadCount:   unreadCount,
+		Notifications: make([]notification, len(notifications)),
+	}
+	for i, currentNotification := range notifications {
+		respBody.Notifications[i] = notification{
+			ID:         currentNotification.ID,
+			Cursor:     strconv.FormatInt(currentNotification.Seq, 10),
+			Type:       currentNotification.Type,
+			Actors:     actorsByNotification[currentNotification.ID],
+			ActorCount: currentNotification.ActorCount,
+			CreatedAt:  currentNotification.CreatedAt,
+			UpdatedAt:  currentNotification.UpdatedAt,
+			Read:       currentNotification.ReadAt.Valid,
+		}
+		if respBody.Notifications[i].Actors == nil {
+			respBody.Notifications[i].Actors = []notificationActor{}
+		}
+		if currentNotification.ChirpID.Valid {
+			respBody.Notifications[i].ChirpID = &currentNotification.ChirpID.UUID
+		}
+	}
+	if len(notifications) == int(limit) {
+		respBody.NextCursor = respBody.Notifications[len(notifications)-1].Cursor
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
+
+func (cfg *apiConfig) handlerPostNotificationsRead(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := struct {
+		Cursor string `json:"cursor"`
+	}{}
+	err := decoder.Decode(&params)
+	if err != nil && err != io.EOF {
+		log.Printf("failed to decode parameters: %s", err)
+		respondWithError(w, 400, "Invalid request body")
+		return
+	}
+
+	// Without a cursor everything is marked as read.
+	seq := int64(math.MaxInt64)
+	if params.Cursor != "" {
+		seq, err = strconv.ParseInt(params.Cursor, 10, 64)
+		if err != nil {
+			log.Printf("failed to parse cursor: %s", err)
+			respondWithError(w, 400, "Invalid cursor")
+			return
+		}
+	}
+
+	err = cfg.dbQueries.MarkNotificationsRead(req.Context(), database.MarkNotificationsReadParams{
+		UserID: userID,
+		Seq:    seq,
+	})
+	if err != nil {
+		log.Printf("failed to mark notifications as read: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerGetNotificationPreferences(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	cfg.respondWithNotificationPreferences(w, req, userID)
+}
+
+func (cfg *apiConfig) handlerPutNotificationPreferences(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := map[string]bool{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		log.Printf("failed to decode parameters: %s", err)
+		respondWithError(w, 400, "Invalid request body")
+		return
+	}
+
+	for notificationType := range params {
+		if !slices.Contains(notificationTypes, notificationType) {
+			respondWithError(w, 400, fmt.Sprintf("Unknown notification type %q", notificationType))
+			return
+		}
+	}
+
+	tx, err := cfg.db.BeginTx(req.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	for notificationType, enabled := range params {
+		err = qtx.UpsertNotificationPreference(req.Context(), database.UpsertNotificationPreferenceParams{
+			UserID:  userID,
+			Type:    notificationType,
+			Enabled: enabled,
+		})
+		if err != nil {
+			log.Printf("failed to save notification preference: %s", err)
+			respondWithDBError(w, err)
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("failed to commit notification preferences: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	cfg.respondWithNotificationPreferences(w, req, userID)
+}
+
+// respondWithNotificationPreferences lists every notification type and
+// whether the user receives it.
+func (cfg *apiConfig) respondWithNotificationPreferences(w http.ResponseWriter, req *http.Request, userID uuid.UUID) {
+	preferences, err := cfg.dbQueries.GetNotificationPreferences(req.Context(), userID)
+	if err != nil {
+		log.Printf("failed to get notification preferences: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := map[string]bool{}
+	for _, notificationType := range notificationTypes {
+		respBody[notificationType] = true
+	}
+	for _, preference := range preferences {
+		respBody[preference.Type] = preference.Enabled
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
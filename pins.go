@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	maxPinnedChirps          = 3
+	maxPinnedChirpsChirpyRed = 10
+)
+
+func pinLimit(isChirpyRed bool) int {
+	if isChirpyRed {
+		return maxPinnedChirpsChirpyRed
+	}
+	return maxPinnedChirps
+}
+
+// pinnedChirps returns the user's pinned chirps that the viewer may see,
+// most recently pinned first.
+func (cfg *apiConfig) pinnedChirps(ctx context.Context, userID uuid.UUID, viewerID uuid.NullUUID) ([]chirp, error) {
+	pinned, err := cfg.dbQueries.GetPinnedChirps(ctx, database.GetPinnedChirpsParams{
+		UserID:   userID,
+		ViewerID: viewerID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pinned chirps: %w", err)
+	}
+
+	respBody, err := cfg.buildChirps(ctx, pinned, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range respBody {
+		respBody[i].Pinned = true
+	}
+	return respBody, nil
+}
+
+func (cfg *apiConfig) handlerPostChirpPin(w http.ResponseWriter, req *http.Request) {
+	chirpByID, ok := cfg.ownedChirp(w, req)
+	if !ok {
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(req.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	// The lock keeps two concurrent pins from both fitting under the limit.
+	err = qtx.LockUserChirps(req.Context(), chirpByID.UserID)
+	if err != nil {
+		log.Printf("failed to lock user chirps: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	author, err := qtx.GetUserByID(req.Context(), chirpByID.UserID)
+	if err != nil {
+		log.Printf("failed to get user: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	pinnedIDs, err := qtx.GetPinnedChirpIDs(req.Context(), chirpByID.UserID)
+	if err != nil {
+		log.Printf("failed to get pinned chirps: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	if slices.Contains(pinnedIDs, chirpByID.ID) {
+		w.WriteHeader(204)
+		return
+	}
+	if limit := pinLimit(author.IsChirpyRed); len(pinnedIDs) >= limit {
+		respondWithError(w, 400, fmt.Sprintf("You can pin at most %d chirps", limit))
+		return
+	}
+
+	err = qtx.CreatePinnedChirp(req.Context(), database.CreatePinnedChirpParams{
+		UserID:  chirpByID.UserID,
+		ChirpID: chirpByID.ID,
+	})
+	if err != nil {
+		log.Printf("failed to pin chirp: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("failed to commit pin: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerDeleteChirpPin(w http.ResponseWriter, req *http.Request) {
+	chirpByID, ok := cfg.ownedChirp(w, req)
+	if !ok {
+		return
+	}
+
+	err := cfg.dbQueries.DeletePinnedChirp(req.Context(), database.DeletePinnedChirpParams{
+		UserID:  chirpByID.UserID,
+		ChirpID: chirpByID.ID,
+	})
+	if err != nil {
+		log.Printf("failed to unpin chirp: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(204)
+}
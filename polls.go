@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	minPollOptions      = 2
+	maxPollOptions      = 4
+	maxPollOptionLength = 25
+	maxPollDuration     = 7 * 24 * time.Hour
+)
+
+type poll struct {
+	ID            uuid.UUID    `json:"id"`
+	ClosesAt      time.Time    `json:"closes_at"`
+	Closed        bool         `json:"closed"`
+	VotedOptionID *uuid.UUID   `json:"voted_option_id,omitempty"`
+	TotalVotes    *int32       `json:"total_votes,omitempty"`
+	Options       []pollOption `json:"options"`
+}
+
+// VoteCount is only set once the viewer may see the results.
+type pollOption struct {
+	ID        uuid.UUID `json:"id"`
+	Label     string    `json:"label"`
+	VoteCount *int32    `json:"vote_count,omitempty"`
+}
+
+type pollParameters struct {
+	Options  []string  `json:"options"`
+	ClosesAt time.Time `json:"closes_at"`
+}
+
+func (p pollParameters) validate(now time.Time) error {
+	if len(p.Options) < minPollOptions || len(p.Options) > maxPollOptions {
+		return fmt.Errorf("a poll needs %d to %d options", minPollOptions, maxPollOptions)
+	}
+
+	seen := map[string]bool{}
+	for _, option := range p.Options {
+		label := strings.TrimSpace(option)
+		if label == "" || utf8.RuneCountInString(label) > maxPollOptionLength {
+			return fmt.Errorf("poll options must be 1 to %d characters long", maxPollOptionLength)
+		}
+		if seen[strings.ToLower(label)] {
+			return fmt.Errorf("poll options must be distinct")
+		}
+		seen[strings.ToLower(label)] = true
+	}
+
+	if !p.ClosesAt.After(now) || p.ClosesAt.After(now.Add(maxPollDuration)) {
+		return fmt.Errorf("poll must close within %s", maxPollDuration)
+	}
+	return nil
+}
+
+// savePoll creates the poll of a newly created chirp.
+func savePoll(ctx context.Context, q *database.Queries, createdChirp database.Chirp, params pollParameters) error {
+	createdPoll, err := q.CreatePoll(ctx, database.CreatePollParams{
+		ChirpID:  createdChirp.ID,
+		ClosesAt: params.ClosesAt.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create poll: %w", err)
+	}
+
+	for i, option := range params.Options {
+		err = q.CreatePollOption(ctx, database.CreatePollOptionParams{
+			PollID:   createdPoll.ID,
+			Position: int32(i),
+			Label:    strings.TrimSpace(option),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create poll option: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadPolls returns the polls attached to the given chirps, keyed by chirp ID.
+// Tallies are hidden until the viewer has voted or the poll has closed.
+func (cfg *apiConfig) loadPolls(ctx context.Context, chirpIDs []uuid.UUID, viewerID uuid.NullUUID) (map[uuid.UUID]*poll, error) {
+	polls := map[uuid.UUID]*poll{}
+	if len(chirpIDs) == 0 {
+		return polls, nil
+	}
+
+	dbPolls, err := cfg.dbQueries.GetPollsByChirpIDs(ctx, chirpIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get polls: %w", err)
+	}
+	if len(dbPolls) == 0 {
+		return polls, nil
+	}
+
+	pollIDs := make([]uuid.UUID, len(dbPolls))
+	for i, dbPoll := range dbPolls {
+		pollIDs[i] = dbPoll.ID
+	}
+
+	options, err := cfg.dbQueries.GetPollOptionsByPollIDs(ctx, pollIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll options: %w", err)
+	}
+
+	votes := map[uuid.UUID]uuid.UUID{}
+	if viewerID.Valid {
+		pollVotes, err := cfg.dbQueries.GetPollVotesByUser(ctx, database.GetPollVotesByUserParams{
+			UserID:  viewerID.UUID,
+			PollIds: pollIDs,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get poll votes: %w", err)
+		}
+		for _, vote := range pollVotes {
+			votes[vote.PollID] = vote.OptionID
+		}
+	}
+
+	byPollID := map[uuid.UUID]*poll{}
+	now := time.Now().UTC()
+	for _, dbPoll := range dbPolls {
+		currentPoll := &poll{
+			ID:       dbPoll.ID,
+			ClosesAt: dbPoll.ClosesAt,
+			Closed:   dbPoll.FinalizedAt.Valid || !dbPoll.ClosesAt.After(now),
+			Options:  []pollOption{},
+		}
+		if optionID, ok := votes[dbPoll.ID]; ok {
+			currentPoll.VotedOptionID = &optionID
+		}
+		if currentPoll.Closed || currentPoll.VotedOptionID != nil {
+			currentPoll.TotalVotes = new(int32)
+		}
+		polls[dbPoll.ChirpID] = currentPoll
+		byPollID[dbPoll.ID] = currentPoll
+	}
+
+	for _, option := range options {
+		currentPoll := byPollID[option.PollID]
+		currentOption := pollOption{
+			ID:    option.ID,
+			Label: option.Label,
+		}
+		if currentPoll.TotalVotes != nil {
+			voteCount := option.VoteCount
+			currentOption.VoteCount = &voteCount
+			*currentPoll.TotalVotes += voteCount
+		}
+		currentPoll.Options = append(currentPoll.Options, currentOption)
+	}
+
+	return polls, nil
+}
+
+// finalizePolls freezes the tallies of polls whose closing time has passed.
+func (cfg *apiConfig) finalizePolls(ctx context.Context) error {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	err = qtx.RecountClosedPolls(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to recount closed polls: %w", err)
+	}
+
+	finalized, err := qtx.FinalizeClosedPolls(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to finalize closed polls: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit finalized polls: %w", err)
+	}
+
+	if finalized > 0 {
+		log.Printf("finalized %d polls", finalized)
+	}
+	return nil
+}
+
+func (cfg *apiConfig) handlerPostPollVotes(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		log.Printf("failed to parse chirpID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid chirp ID")
+		return
+	}
+
+	params := struct {
+		OptionID uuid.UUID `json:"option_id"`
+	}{}
+	decoder := json.NewDecoder(req.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		log.Printf("failed to decode parameters: %s", err)
+		respondWithError(w, 400, "Invalid parameters")
+		return
+	}
+
+	chirpPoll, err := cfg.dbQueries.GetPollByChirpID(req.Context(), database.GetPollByChirpIDParams{
+		ChirpID:  chirpID,
+		ViewerID: uuid.NullUUID{UUID: userID, Valid: true},
+	})
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to get poll, Id not found: %s", err)
+		respondWithError(w, 404, "Poll not found")
+		return
+	default:
+		log.Printf("failed to get poll: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	if chirpPoll.FinalizedAt.Valid || !chirpPoll.ClosesAt.After(time.Now().UTC()) {
+		respondWithError(w, 409, "Poll is closed")
+		return
+	}
+
+	// The insert re-checks the option and closing time, and the primary key
+	// rejects a second vote, so nothing here depends on the checks above.
+	voted, err := cfg.dbQueries.CreatePollVote(req.Context(), database.CreatePollVoteParams{
+		UserID:   userID,
+		PollID:   chirpPoll.ID,
+		OptionID: params.OptionID,
+	})
+	if err != nil {
+		log.Printf("failed to create poll vote: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	polls, err := cfg.loadPolls(req.Context(), []uuid.UUID{chirpID}, uuid.NullUUID{UUID: userID, Valid: true})
+	if err != nil {
+		log.Printf("failed to load poll: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	currentPoll := polls[chirpID]
+
+	if voted == 0 {
+		switch {
+		case currentPoll.VotedOptionID != nil:
+			respondWithError(w, 409, "Already voted")
+		case currentPoll.Closed:
+			respondWithError(w, 409, "Poll is closed")
+		default:
+			respondWithError(w, 400, "Invalid option")
+		}
+		return
+	}
+
+	respondWithJSON(w, 201, currentPoll)
+}
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/chirptext"
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+// profile is the public view of a user, without the email address and
+// other account details that only the user sees. Their own profile also
+// carries their settings, such as dms_from_following_only.
+type profile struct {
+	ID                   uuid.UUID `json:"id"`
+	CreatedAt            time.Time `json:"created_at"`
+	Handle               string    `json:"handle,omitempty"`
+	DisplayName          string    `json:"display_name"`
+	Bio                  string    `json:"bio"`
+	Location             string    `json:"location"`
+	Website              string    `json:"website"`
+	AvatarURL            string    `json:"avatar_url,omitempty"`
+	AvatarThumbnailURL   string    `json:"avatar_thumbnail_url,omitempty"`
+	IsChirpyRed          bool      `json:"is_chirpy_red"`
+	IsPrivate            bool      `json:"is_private"`
+	DMsFromFollowingOnly *bool     `json:"dms_from_following_only,omitempty"`
+	FollowerCount        int32     `json:"follower_count"`
+	FollowingCount       int32     `json:"following_count"`
+	PinnedChirps         []chirp   `json:"pinned_chirps"`
+}
+
+// profileParameters is a decoded profile patch. Nil fields are left as they
+// are.
+type profileParameters struct {
+	Handle      *string
+	ClearHandle bool
+	DisplayName *string
+	Bio         *string
+	Location    *string
+	Website     *string
+	IsPrivate   *bool
+	// DMsFromFollowingOnly only lets people the user follows start
+	// conversations with them.
+	DMsFromFollowingOnly *bool
+}
+
+func buildProfile(row database.GetProfileByIDRow) profile {
+	respBody := profile{
+		ID:             row.ID,
+		CreatedAt:      row.CreatedAt,
+		Handle:         row.Handle.String,
+		DisplayName:    row.DisplayName,
+		Bio:            row.Bio,
+		Location:       row.Location,
+		Website:        row.Website,
+		IsChirpyRed:    row.IsChirpyRed,
+		IsPrivate:      row.IsPrivate,
+		FollowerCount:  row.FollowerCount,
+		FollowingCount: row.FollowingCount,
+	}
+	if row.AvatarKey.Valid {
+		respBody.AvatarURL = "/api/media/" + row.AvatarKey.String
+		respBody.AvatarThumbnailURL = "/api/media/" + row.AvatarThumbnailKey.String
+	}
+	return respBody
+}
+
+// invalidProfileError reports a profile update rejected because of one of its
+// fields. Its message is shown to the client.
+type invalidProfileError struct {
+	msg string
+}
+
+func (e invalidProfileError) Error() string {
+	return e.msg
+}
+
+// decodeProfilePatch reads a JSON Merge Patch (RFC 7396) of the caller's
+// profile. Members left out keep their value and members set to null are
+// reset: text fields become empty, the handle is removed, the account
+// becomes public and accepts messages from everyone.
+func decodeProfilePatch(body io.Reader) (profileParameters, error) {
+	patch := map[string]json.RawMessage{}
+	err := json.NewDecoder(body).Decode(&patch)
+	if err != nil {
+		return profileParameters{}, invalidProfileError{"Request body must be a JSON object"}
+	}
+
+	params := profileParameters{}
+	textFields := map[string]**string{
+		"display_name": &params.DisplayName,
+		"bio":          &params.Bio,
+		"location":     &params.Location,
+		"website":      &params.Website,
+	}
+	for name, value := range patch {
+		isNull := bytes.Equal(bytes.TrimSpace(value), []byte("null"))
+		switch {
+		case name == "handle" && isNull:
+			params.ClearHandle = true
+		case name == "handle":
+			err = json.Unmarshal(value, &params.Handle)
+		case textFields[name] != nil && isNull:
+			*textFields[name] = new(string)
+		case textFields[name] != nil:
+			err = json.Unmarshal(value, textFields[name])
+		case name == "is_private" && isNull:
+			params.IsPrivate = new(bool)
+		case name == "is_private":
+			err = json.Unmarshal(value, &params.IsPrivate)
+		case name == "dms_from_following_only" && isNull:
+			params.DMsFromFollowingOnly = new(bool)
+		case name == "dms_from_following_only":
+			err = json.Unmarshal(value, &params.DMsFromFollowingOnly)
+		case name == "email" || name == "password":
+			return profileParameters{}, invalidProfileError{"Use /api/users/me/email or /api/users/me/password to change credentials"}
+		default:
+			return profileParameters{}, invalidProfileError{fmt.Sprintf("Unknown field %q", name)}
+		}
+		if err != nil {
+			return profileParameters{}, invalidProfileError{fmt.Sprintf("Invalid value for %s", name)}
+		}
+	}
+	return params, nil
+}
+
+// validate normalizes the fields being updated and reports the first invalid
+// one.
+func (p *profileParameters) validate() error {
+	if p.Handle != nil && !chirptext.ValidHandle(*p.Handle) {
+		return invalidProfileError{"Invalid handle"}
+	}
+
+	fields := []struct {
+		name      string
+		value     *string
+		maxLength int
+		multiline bool
+	}{
+		{"Display name", p.DisplayName, chirptext.MaxDisplayNameLength, false},
+		{"Bio", p.Bio, chirptext.MaxBioLength, true},
+		{"Location", p.Location, chirptext.MaxLocationLength, false},
+	}
+	for _, field := range fields {
+		if field.value == nil {
+			continue
+		}
+		text, err := chirptext.ValidateTextField(*field.value, field.maxLength, field.multiline)
+		switch err {
+		case nil:
+			*field.value = text
+		case chirptext.ErrFieldTooLong:
+			return invalidProfileError{fmt.Sprintf("%s is too long, the limit is %d characters", field.name, field.maxLength)}
+		default:
+			return invalidProfileError{fmt.Sprintf("%s contains control characters", field.name)}
+		}
+	}
+
+	if p.Website != nil {
+		website, err := chirptext.ValidateWebsite(*p.Website)
+		switch err {
+		case nil:
+			*p.Website = website
+		case chirptext.ErrFieldTooLong:
+			return invalidProfileError{fmt.Sprintf("Website is too long, the limit is %d characters", chirptext.MaxWebsiteLength)}
+		default:
+			return invalidProfileError{"Website must be an http or https URL"}
+		}
+	}
+	return nil
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func (cfg *apiConfig) handlerGetUserProfile(w http.ResponseWriter, req *http.Request) {
+	row, err := cfg.dbQueries.GetProfileByHandle(req.Context(), database.GetProfileByHandleParams{
+		Handle:   chirptext.NormalizeHandle(req.PathValue("handle")),
+		ViewerID: cfg.viewerID(req),
+	})
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to get profile, handle not found: %s", err)
+		respondWithError(w, 404, "User not found")
+		return
+	default:
+		log.Printf("failed to get profile: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := buildProfile(database.GetProfileByIDRow(row))
+	respBody.PinnedChirps, err = cfg.pinnedChirps(req.Context(), row.ID, cfg.viewerID(req))
+	if err != nil {
+		log.Printf("failed to build pinned chirps: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
+
+func (cfg *apiConfig) handlerPatchUsersMe(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType != "" && mediaType != "application/merge-patch+json" && mediaType != "application/json" {
+		respondWithError(w, 415, "Content-Type must be application/merge-patch+json")
+		return
+	}
+
+	params, err := decodeProfilePatch(req.Body)
+	if err == nil {
+		err = params.validate()
+	}
+	if err != nil {
+		respondWithError(w, 400, err.Error())
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(req.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	myParams := database.UpdateUserProfileParams{
+		ID:          userID,
+		ClearHandle: params.ClearHandle,
+		Handle:      nullString(params.Handle),
+		DisplayName: nullString(params.DisplayName),
+		Bio:         nullString(params.Bio),
+		Location:    nullString(params.Location),
+		Website:     nullString(params.Website),
+	}
+	if params.IsPrivate != nil {
+		myParams.IsPrivate = sql.NullBool{Bool: *params.IsPrivate, Valid: true}
+	}
+	if params.DMsFromFollowingOnly != nil {
+		myParams.DmsFromFollowingOnly = sql.NullBool{Bool: *params.DMsFromFollowingOnly, Valid: true}
+	}
+
+	_, err = qtx.UpdateUserProfile(req.Context(), myParams)
+	// Going public lets everyone follow, so waiting requests are accepted
+	// along the way.
+	if err == nil && params.IsPrivate != nil && !*params.IsPrivate {
+		err = qtx.AcceptAllFollowRequests(req.Context(), userID)
+	}
+	if isUniqueViolation(err) {
+		log.Printf("failed to update profile: %s", err)
+		respondWithError(w, 409, "Handle already taken")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to update profile: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("failed to commit profile update: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	cfg.respondWithProfile(w, req, userID)
+}
+
+func (cfg *apiConfig) handlerPostUsersMeAvatar(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	item, ok := cfg.uploadImage(w, req, userID)
+	if !ok {
+		return
+	}
+
+	err := cfg.replaceAvatar(req, userID, uuid.NullUUID{UUID: item.ID, Valid: true})
+	if err != nil {
+		log.Printf("failed to set avatar: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	cfg.respondWithProfile(w, req, userID)
+}
+
+func (cfg *apiConfig) handlerDeleteUsersMeAvatar(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	err := cfg.replaceAvatar(req, userID, uuid.NullUUID{})
+	if err != nil {
+		log.Printf("failed to remove avatar: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+// replaceAvatar sets the user's avatar and deletes the previous one, whose
+// blobs are then removed by the purge worker.
+func (cfg *apiConfig) replaceAvatar(req *http.Request, userID uuid.UUID, avatarID uuid.NullUUID) error {
+	tx, err := cfg.db.BeginTx(req.Context(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	currentUser, err := qtx.GetUserByID(req.Context(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	err = qtx.SetUserAvatar(req.Context(), database.SetUserAvatarParams{
+		ID:       userID,
+		AvatarID: avatarID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set avatar: %w", err)
+	}
+
+	if currentUser.AvatarID.Valid {
+		err = qtx.DeleteMediaItem(req.Context(), currentUser.AvatarID.UUID)
+		if err != nil {
+			return fmt.Errorf("failed to delete previous avatar: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (cfg *apiConfig) respondWithProfile(w http.ResponseWriter, req *http.Request, userID uuid.UUID) {
+	row, err := cfg.dbQueries.GetProfileByID(req.Context(), userID)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to get profile, Id not found: %s", err)
+		respondWithError(w, 404, "User not found")
+		return
+	default:
+		log.Printf("failed to get profile: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := buildProfile(row)
+	respBody.DMsFromFollowingOnly = &row.DmsFromFollowingOnly
+	respBody.PinnedChirps, err = cfg.pinnedChirps(req.Context(), userID, uuid.NullUUID{UUID: userID, Valid: true})
+	if err != nil {
+		log.Printf("failed to build pinned chirps: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
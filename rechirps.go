@@ -0,0 +1,152 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerPostRechirp(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		log.Printf("failed to parse chirpID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid chirp ID")
+		return
+	}
+
+	original, err := cfg.dbQueries.GetChirpByID(req.Context(), database.GetChirpByIDParams{
+		ID:       chirpID,
+		ViewerID: uuid.NullUUID{UUID: userID, Valid: true},
+	})
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to get chirp, Id not found: %s", err)
+		respondWithError(w, 404, "Chirp not found")
+		return
+	default:
+		log.Printf("failed to get chirp: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	// Rechirps are public, so they may only amplify public chirps.
+	if original.Visibility != visibilityPublic {
+		respondWithError(w, 403, "Only public chirps can be rechirped")
+		return
+	}
+
+	// Rechirping a rechirp amplifies the chirp it points to.
+	originalID := uuid.NullUUID{UUID: original.ID, Valid: true}
+	originalAuthorID := original.UserID
+	if original.RechirpOfID.Valid {
+		originalID = original.RechirpOfID
+		root, err := cfg.dbQueries.GetChirpByID(req.Context(), database.GetChirpByIDParams{
+			ID:       originalID.UUID,
+			ViewerID: uuid.NullUUID{UUID: userID, Valid: true},
+		})
+		switch err {
+		case nil:
+		case sql.ErrNoRows:
+			log.Printf("failed to get chirp, Id not found: %s", err)
+			respondWithError(w, 404, "Chirp not found")
+			return
+		default:
+			log.Printf("failed to get chirp: %s", err)
+			respondWithError(w, 500, "Internal server error")
+			return
+		}
+		originalAuthorID = root.UserID
+	}
+
+	tx, err := cfg.db.BeginTx(req.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	status := 201
+	// The rechirp takes the original's expires_at, so it disappears with it.
+	rechirp, err := qtx.CreateRechirp(req.Context(), database.CreateRechirpParams{
+		UserID:      userID,
+		RechirpOfID: originalID.UUID,
+	})
+	if err == nil {
+		err = qtx.FanOutChirp(req.Context(), database.FanOutChirpParams{
+			ChirpID:     rechirp.ID,
+			FanoutLimit: cfg.fanOutLimit,
+		})
+	}
+	if err == sql.ErrNoRows {
+		status = 200
+		rechirp, err = qtx.GetRechirp(req.Context(), database.GetRechirpParams{
+			UserID:      userID,
+			RechirpOfID: originalID,
+		})
+	}
+	if err != nil {
+		log.Printf("failed to create rechirp: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("failed to commit rechirp: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	if status == 201 {
+		err = notify(req.Context(), cfg.dbQueries, notificationRechirp, originalAuthorID, userID, originalID)
+		if err != nil {
+			log.Printf("failed to notify chirp author: %s", err)
+		}
+	}
+
+	respBody, err := cfg.buildChirps(req.Context(), []database.Chirp{rechirp}, uuid.NullUUID{UUID: userID, Valid: true})
+	if err != nil {
+		log.Printf("failed to build chirp response: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respondWithJSON(w, status, respBody[0])
+}
+
+func (cfg *apiConfig) handlerDeleteRechirp(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		log.Printf("failed to parse chirpID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid chirp ID")
+		return
+	}
+
+	err = cfg.dbQueries.DeleteRechirp(req.Context(), database.DeleteRechirpParams{
+		UserID:      userID,
+		RechirpOfID: uuid.NullUUID{UUID: chirpID, Valid: true},
+	})
+	if err != nil {
+		log.Printf("failed to delete rechirp: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(204)
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/auth"
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerPostChirpRestore(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		log.Printf("failed to parse chirpID string to uuid: %s", err)
+		respondWithError(w, 400, "Invalid chirp ID")
+		return
+	}
+
+	deletedChirp, err := cfg.dbQueries.GetDeletedChirpByID(req.Context(), chirpID)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		log.Printf("failed to get deleted chirp, Id not found: %s", err)
+		respondWithError(w, 404, "Chirp not found")
+		return
+	default:
+		log.Printf("failed to get deleted chirp: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	// Rechirps are only deleted along with the chirp they amplify, and come
+	// back when it is restored.
+	if deletedChirp.RechirpOfID.Valid {
+		respondWithError(w, 404, "Chirp not found")
+		return
+	}
+
+	if userID != deletedChirp.UserID {
+		log.Printf("Not owner of the chirp")
+		respondWithError(w, 403, "Unauthorized")
+		return
+	}
+
+	if time.Since(deletedChirp.DeletedAt.Time) > cfg.restoreWindow {
+		respondWithError(w, 410, "Restore window has expired")
+		return
+	}
+
+	// Deleting a chirp unpins it, so a restored chirp comes back unpinned
+	// and can't take the author over the pin limit.
+	err = cfg.dbQueries.RestoreChirp(req.Context(), database.RestoreChirpParams{
+		ID:        deletedChirp.ID,
+		DeletedAt: deletedChirp.DeletedAt.Time,
+	})
+	if err != nil {
+		log.Printf("failed to restore chirp: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	deletedChirp.DeletedAt = sql.NullTime{}
+	respBody, err := cfg.buildChirps(req.Context(), []database.Chirp{deletedChirp}, uuid.NullUUID{UUID: userID, Valid: true})
+	if err != nil {
+		log.Printf("failed to build chirp response: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respondWithJSON(w, 200, respBody[0])
+}
+
+func (cfg *apiConfig) handlerDeleteUsers(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(req.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	deleted, err := qtx.SoftDeleteUser(req.Context(), userID)
+	if err != nil {
+		log.Printf("failed to delete user: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, 404, "User not found")
+		return
+	}
+
+	// Within the transaction NOW() doesn't change, so the chirps share the
+	// account's deleted_at and can be told apart from ones deleted earlier.
+	err = qtx.SoftDeleteChirpsByUser(req.Context(), userID)
+	if err != nil {
+		log.Printf("failed to delete user chirps: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	err = qtx.RevokeUserRefreshTokens(req.Context(), userID)
+	if err != nil {
+		log.Printf("failed to revoke refresh tokens: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("failed to commit user deletion: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerPostUsersRestore(w http.ResponseWriter, req *http.Request) {
+	type parameters struct {
+		Password string `json:"password"`
+		Email    string `json:"email"`
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		log.Printf("failed to decode parameters: %v", err)
+		respondWithError(w, 400, "Invalid parameters")
+		return
+	}
+
+	deletedUser, err := cfg.dbQueries.GetDeletedUserByEmail(req.Context(), params.Email)
+	if err != nil {
+		log.Printf("failed to get the deleted user by email: %v", err)
+		respondWithError(w, 401, "Unauthorized")
+		return
+	}
+
+	match, err := auth.CheckPasswordHash(params.Password, deletedUser.HashedPassword)
+	if !match || err != nil {
+		log.Printf("failed to check password: %v", err)
+		respondWithError(w, 401, "Unauthorized")
+		return
+	}
+
+	if time.Since(deletedUser.DeletedAt.Time) > cfg.restoreWindow {
+		respondWithError(w, 410, "Restore window has expired")
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(req.Context(), nil)
+	if err != nil {
+		log.Printf("failed to begin transaction: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	restoredUser, err := qtx.RestoreUser(req.Context(), deletedUser.ID)
+	if err != nil {
+		log.Printf("failed to restore user: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	err = qtx.RestoreChirpsByUser(req.Context(), database.RestoreChirpsByUserParams{
+		UserID:    deletedUser.ID,
+		DeletedAt: deletedUser.DeletedAt.Time,
+	})
+	if err != nil {
+		log.Printf("failed to restore user chirps: %s", err)
+		respondWithDBError(w, err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("failed to commit user restore: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respondWithJSON(w, 200, buildUser(restoredUser))
+}
+
+// purgeDeleted permanently removes the chirps and accounts deleted longer ago
+// than the restore window. Their likes, hashtags, media and other dependent
+// rows go with them through ON DELETE CASCADE, and blobs left unreferenced
+// are then removed from the blob store.
+func (cfg *apiConfig) purgeDeleted(ctx context.Context) error {
+	purgedUsers, err := cfg.dbQueries.PurgeDeletedUsers(ctx, cfg.restoreWindow.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to purge deleted users: %w", err)
+	}
+
+	purgedChirps, err := cfg.dbQueries.PurgeDeletedChirps(ctx, cfg.restoreWindow.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to purge deleted chirps: %w", err)
+	}
+
+	if purgedUsers > 0 || purgedChirps > 0 {
+		log.Printf("purged %d deleted users and %d deleted chirps", purgedUsers, purgedChirps)
+	}
+
+	return cfg.deleteUnreferencedBlobs(ctx)
+}
+
+func (cfg *apiConfig) deleteUnreferencedBlobs(ctx context.Context) error {
+	for ctx.Err() == nil {
+		claimed, err := cfg.deleteNextBlob(ctx)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return nil
+		}
+	}
+	return nil
+}
+
+// deleteNextBlob claims one queued blob deletion with FOR UPDATE SKIP LOCKED,
+// so replicas share the queue, and reports whether there was one. The blob is
+// kept if a media item has started using it again since it was queued.
+// Uploads write their media item before the blob, which waits on the claimed
+// row, so a re-upload of the same content stores the blob after it is deleted.
+func (cfg *apiConfig) deleteNextBlob(ctx context.Context) (bool, error) {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	deletion, err := qtx.ClaimBlobDeletion(ctx)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim blob deletion: %w", err)
+	}
+
+	referenced, err := qtx.IsBlobReferenced(ctx, deletion.BlobKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to check blob references: %w", err)
+	}
+	if !referenced {
+		err = cfg.blobStore.Delete(ctx, deletion.BlobKey)
+		if err != nil {
+			return false, fmt.Errorf("failed to delete blob %s: %w", deletion.BlobKey, err)
+		}
+	}
+
+	err = qtx.DeleteBlobDeletion(ctx, deletion.BlobKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete blob deletion: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, fmt.Errorf("failed to commit blob deletion: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,4 @@
+-- name: AcceptAllFollowRequests :exec
+UPDATE follows
+SET status = 'accepted'
+WHERE followee_id = $1 AND status = 'pending';
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- name: AcceptFollowRequest :execrows
+UPDATE follows
+SET status = 'accepted'
+WHERE follower_id = $1 AND followee_id = $2 AND status = 'pending';
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- name: AddConversationParticipant :exec
+INSERT INTO conversation_participants (conversation_id, user_id, joined_at)
+VALUES (
+    $1,
+    $2,
+    NOW()
+);
\ No newline at end of file
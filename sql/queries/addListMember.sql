@@ -0,0 +1,5 @@
+-- name: AddListMember :execrows
+INSERT INTO list_members (list_id, user_id, created_at)
+SELECT sqlc.arg(list_id)::uuid, users.id, NOW() FROM users
+WHERE users.id = sqlc.arg(user_id) AND users.deleted_at IS NULL AND NOT users_blocked(users.id, sqlc.arg(owner_id)::uuid)
+ON CONFLICT (list_id, user_id) DO UPDATE SET created_at = list_members.created_at;
\ No newline at end of file
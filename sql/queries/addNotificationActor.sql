@@ -0,0 +1,8 @@
+-- name: AddNotificationActor :exec
+INSERT INTO notification_actors (notification_id, actor_id, created_at)
+VALUES (
+    $1,
+    $2,
+    NOW()
+)
+ON CONFLICT (notification_id, actor_id) DO UPDATE SET created_at = NOW();
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- name: AttachMediaItem :execrows
+UPDATE media_items
+SET chirp_id = $1,
+    position = $2
+WHERE id = $3 AND user_id = $4 AND chirp_id IS NULL
+AND NOT EXISTS (SELECT 1 FROM users WHERE users.avatar_id = media_items.id);
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- name: BackfillTimeline :exec
+INSERT INTO timeline_entries (user_id, chirp_id, author_id, created_at)
+SELECT sqlc.arg(follower_id)::uuid, chirps.id, chirps.user_id, chirps.created_at FROM chirps
+WHERE chirps.user_id = sqlc.arg(followee_id) AND chirps.deleted_at IS NULL AND chirps.fanned_out
+ORDER BY chirps.created_at DESC
+LIMIT sqlc.arg('limit')
+ON CONFLICT (user_id, chirp_id) DO NOTHING;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: ClaimBlobDeletion :one
+SELECT * FROM blob_deletions
+ORDER BY created_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
\ No newline at end of file
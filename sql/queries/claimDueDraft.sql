@@ -0,0 +1,7 @@
+-- name: ClaimDueDraft :one
+SELECT * FROM drafts
+WHERE publish_at <= NOW() AND failed_at IS NULL AND (retry_at IS NULL OR retry_at <= NOW())
+AND NOT EXISTS (SELECT 1 FROM users WHERE users.id = drafts.user_id AND users.deleted_at IS NOT NULL)
+ORDER BY publish_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
\ No newline at end of file
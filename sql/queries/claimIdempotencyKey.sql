@@ -0,0 +1,18 @@
+-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys (scope, key, fingerprint, created_at, expires_at)
+VALUES (
+    $1,
+    $2,
+    $3,
+    NOW(),
+    NOW() + INTERVAL '24 hours'
+)
+ON CONFLICT (scope, key) DO UPDATE
+SET fingerprint = EXCLUDED.fingerprint,
+    created_at = EXCLUDED.created_at,
+    expires_at = EXCLUDED.expires_at,
+    response_status = NULL,
+    response_content_type = NULL,
+    response_body = NULL
+WHERE idempotency_keys.expires_at <= NOW()
+RETURNING *;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: ClaimTimelineBackfill :one
+SELECT * FROM timeline_backfills
+ORDER BY created_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
\ No newline at end of file
@@ -0,0 +1,25 @@
+-- name: ComputeTrendingChirps :exec
+INSERT INTO trending_chirps (period, chirp_id, score, computed_at)
+SELECT
+    sqlc.arg(period)::text,
+    events.chirp_id,
+    SUM(events.weight * POWER(0.5, EXTRACT(EPOCH FROM NOW() - events.created_at) / sqlc.arg(half_life_seconds)::double precision)),
+    NOW()
+FROM (
+    SELECT likes.chirp_id, likes.created_at, 1.0 AS weight
+    FROM likes
+    JOIN chirps ON chirps.id = likes.chirp_id
+    WHERE chirps.deleted_at IS NULL AND chirps.visibility = 'public' AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+    AND likes.created_at > NOW() - make_interval(secs => sqlc.arg(window_seconds)::double precision)
+    UNION ALL
+    SELECT chirps.rechirp_of_id AS chirp_id, chirps.created_at, 2.0 AS weight
+    FROM chirps
+    WHERE chirps.rechirp_of_id IS NOT NULL AND chirps.deleted_at IS NULL
+    AND chirps.created_at > NOW() - make_interval(secs => sqlc.arg(window_seconds)::double precision)
+) AS events
+GROUP BY events.chirp_id
+ORDER BY 3 DESC
+LIMIT sqlc.arg(max_results)::integer
+ON CONFLICT (period, chirp_id) DO UPDATE
+SET score = EXCLUDED.score,
+    computed_at = EXCLUDED.computed_at;
\ No newline at end of file
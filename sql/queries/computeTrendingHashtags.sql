@@ -0,0 +1,29 @@
+-- name: ComputeTrendingHashtags :exec
+INSERT INTO trending_hashtags (period, tag, score, computed_at)
+SELECT
+    sqlc.arg(period)::text,
+    events.tag,
+    SUM(events.weight * POWER(0.5, EXTRACT(EPOCH FROM NOW() - events.created_at) / sqlc.arg(half_life_seconds)::double precision)),
+    NOW()
+FROM (
+    SELECT hashtags.tag, chirps.created_at, 1.0 AS weight
+    FROM chirp_hashtags
+    JOIN hashtags ON hashtags.id = chirp_hashtags.hashtag_id
+    JOIN chirps ON chirps.id = chirp_hashtags.chirp_id
+    WHERE chirps.deleted_at IS NULL AND chirps.visibility = 'public' AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+    AND chirps.created_at > NOW() - make_interval(secs => sqlc.arg(window_seconds)::double precision)
+    UNION ALL
+    SELECT hashtags.tag, likes.created_at, 0.5 AS weight
+    FROM likes
+    JOIN chirps ON chirps.id = likes.chirp_id
+    JOIN chirp_hashtags ON chirp_hashtags.chirp_id = likes.chirp_id
+    JOIN hashtags ON hashtags.id = chirp_hashtags.hashtag_id
+    WHERE chirps.deleted_at IS NULL AND chirps.visibility = 'public' AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+    AND likes.created_at > NOW() - make_interval(secs => sqlc.arg(window_seconds)::double precision)
+) AS events
+GROUP BY events.tag
+ORDER BY 3 DESC
+LIMIT sqlc.arg(max_results)::integer
+ON CONFLICT (period, tag) DO UPDATE
+SET score = EXCLUDED.score,
+    computed_at = EXCLUDED.computed_at;
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- name: CountUnreadMessages :many
+SELECT messages.conversation_id, COUNT(*) AS unread_count FROM messages
+JOIN conversation_participants ON conversation_participants.conversation_id = messages.conversation_id AND conversation_participants.user_id = sqlc.arg(user_id)
+WHERE messages.conversation_id = ANY(sqlc.arg(conversation_ids)::uuid[])
+AND messages.seq > conversation_participants.last_read_seq
+AND messages.sender_id <> sqlc.arg(user_id)
+AND NOT users_blocked(messages.sender_id, sqlc.arg(user_id)::uuid)
+GROUP BY messages.conversation_id;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) FROM notifications
+LEFT JOIN chirps ON chirps.id = notifications.chirp_id
+WHERE notifications.user_id = sqlc.arg(user_id) AND notifications.read_at IS NULL
+AND (notifications.chirp_id IS NULL OR (chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW()) AND chirp_visible_to(chirps.user_id, chirps.visibility, sqlc.arg(user_id)::uuid)));
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- name: CreateBookmark :exec
+INSERT INTO bookmarks (user_id, chirp_id, folder_id, created_at)
+VALUES (
+    sqlc.arg(user_id),
+    sqlc.arg(chirp_id),
+    sqlc.narg(folder_id),
+    NOW()
+)
+ON CONFLICT (user_id, chirp_id) DO UPDATE
+SET folder_id = CASE WHEN sqlc.arg(set_folder)::boolean THEN EXCLUDED.folder_id ELSE bookmarks.folder_id END;
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- name: CreateBookmarkFolder :one
+INSERT INTO bookmark_folders (id, user_id, name, created_at, updated_at)
+VALUES (
+    gen_random_uuid(),
+    $1,
+    $2,
+    NOW(),
+    NOW()
+)
+RETURNING *;
\ No newline at end of file
@@ -1,10 +1,13 @@
 -- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, quoted_chirp_id, visibility, expires_at)
 VALUES (
     gen_random_uuid(),
     NOW(),
     Now(),
     $1,
-    $2
+    $2,
+    $3,
+    $4,
+    $5
 )
 RETURNING *;
\ No newline at end of file
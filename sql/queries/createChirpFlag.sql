@@ -0,0 +1,7 @@
+-- name: CreateChirpFlag :exec
+INSERT INTO chirp_flags (chirp_id, created_at, words)
+VALUES (
+    $1,
+    NOW(),
+    $2
+);
\ No newline at end of file
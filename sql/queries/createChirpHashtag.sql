@@ -0,0 +1,7 @@
+-- name: CreateChirpHashtag :exec
+INSERT INTO chirp_hashtags (chirp_id, hashtag_id)
+VALUES (
+    $1,
+    $2
+)
+ON CONFLICT DO NOTHING;
\ No newline at end of file
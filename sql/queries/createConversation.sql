@@ -0,0 +1,10 @@
+-- name: CreateConversation :one
+INSERT INTO conversations (id, direct_key, created_at, updated_at)
+VALUES (
+    gen_random_uuid(),
+    sqlc.narg(direct_key),
+    NOW(),
+    NOW()
+)
+ON CONFLICT (direct_key) DO NOTHING
+RETURNING *;
\ No newline at end of file
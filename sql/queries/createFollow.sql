@@ -0,0 +1,7 @@
+-- name: CreateFollow :one
+INSERT INTO follows (follower_id, followee_id, created_at, status)
+SELECT sqlc.arg(follower_id)::uuid, users.id, NOW(), CASE WHEN users.is_private THEN 'pending' ELSE 'accepted' END
+FROM users
+WHERE users.id = sqlc.arg(followee_id) AND users.deleted_at IS NULL AND NOT users_blocked(users.id, sqlc.arg(follower_id)::uuid)
+ON CONFLICT (follower_id, followee_id) DO UPDATE SET status = follows.status
+RETURNING *, (xmax = 0)::boolean AS inserted;
\ No newline at end of file
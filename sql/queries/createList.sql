@@ -0,0 +1,12 @@
+-- name: CreateList :one
+INSERT INTO lists (id, owner_id, name, description, is_private, created_at, updated_at)
+VALUES (
+    gen_random_uuid(),
+    $1,
+    $2,
+    $3,
+    $4,
+    NOW(),
+    NOW()
+)
+RETURNING *;
\ No newline at end of file
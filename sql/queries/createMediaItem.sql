@@ -0,0 +1,14 @@
+-- name: CreateMediaItem :one
+INSERT INTO media_items (id, created_at, user_id, content_type, blob_key, thumbnail_key, width, height, size_bytes)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING *;
\ No newline at end of file
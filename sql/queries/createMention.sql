@@ -0,0 +1,9 @@
+-- name: CreateMention :exec
+INSERT INTO mentions (chirp_id, user_id, created_at, handle)
+VALUES (
+    $1,
+    $2,
+    NOW(),
+    $3
+)
+ON CONFLICT DO NOTHING;
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- name: CreateMessage :one
+INSERT INTO messages (id, conversation_id, sender_id, body, created_at)
+VALUES (
+    gen_random_uuid(),
+    $1,
+    $2,
+    $3,
+    NOW()
+)
+RETURNING *;
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- name: CreateMute :exec
+INSERT INTO mutes (muter_id, muted_id, created_at)
+VALUES (
+    $1,
+    $2,
+    NOW()
+)
+ON CONFLICT (muter_id, muted_id) DO NOTHING;
\ No newline at end of file
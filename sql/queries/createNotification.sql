@@ -0,0 +1,15 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (id, user_id, type, chirp_id, group_key, created_at, updated_at)
+SELECT gen_random_uuid(), sqlc.arg(user_id)::uuid, sqlc.arg(type)::text, sqlc.narg(chirp_id)::uuid, sqlc.arg(type)::text || ':' || COALESCE(sqlc.narg(chirp_id)::uuid::text, ''), NOW(), NOW()
+WHERE sqlc.arg(user_id)::uuid <> sqlc.arg(actor_id)::uuid
+AND NOT users_blocked(sqlc.arg(user_id)::uuid, sqlc.arg(actor_id)::uuid)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.arg(user_id)::uuid AND mutes.muted_id = sqlc.arg(actor_id)::uuid
+)
+AND NOT EXISTS (
+    SELECT 1 FROM notification_preferences
+    WHERE notification_preferences.user_id = sqlc.arg(user_id)::uuid AND notification_preferences.type = sqlc.arg(type)::text AND NOT notification_preferences.enabled
+)
+ON CONFLICT (user_id, group_key) WHERE read_at IS NULL DO UPDATE SET updated_at = NOW(), seq = nextval('notification_seq')
+RETURNING id;
\ No newline at end of file
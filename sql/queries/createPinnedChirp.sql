@@ -0,0 +1,8 @@
+-- name: CreatePinnedChirp :exec
+INSERT INTO pinned_chirps (user_id, chirp_id, pinned_at)
+VALUES (
+    $1,
+    $2,
+    NOW()
+)
+ON CONFLICT (user_id, chirp_id) DO NOTHING;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- name: CreatePoll :one
+INSERT INTO polls (id, chirp_id, created_at, closes_at)
+VALUES (
+    gen_random_uuid(),
+    $1,
+    NOW(),
+    $2
+)
+RETURNING *;
\ No newline at end of file
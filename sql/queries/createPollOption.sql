@@ -0,0 +1,8 @@
+-- name: CreatePollOption :exec
+INSERT INTO poll_options (id, poll_id, position, label)
+VALUES (
+    gen_random_uuid(),
+    $1,
+    $2,
+    $3
+);
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- name: CreatePollVote :execrows
+INSERT INTO poll_votes (poll_id, user_id, option_id, created_at)
+SELECT polls.id, sqlc.arg(user_id)::uuid, poll_options.id, NOW()
+FROM polls
+JOIN poll_options ON poll_options.poll_id = polls.id
+WHERE polls.id = sqlc.arg(poll_id) AND poll_options.id = sqlc.arg(option_id) AND polls.closes_at > NOW()
+ON CONFLICT (poll_id, user_id) DO NOTHING;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- name: CreateRechirp :one
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, rechirp_of_id, expires_at)
+SELECT gen_random_uuid(), NOW(), NOW(), '', sqlc.arg(user_id)::uuid, original.id, original.expires_at
+FROM chirps AS original
+WHERE original.id = sqlc.arg(rechirp_of_id)::uuid
+ON CONFLICT (user_id, rechirp_of_id) WHERE rechirp_of_id IS NOT NULL DO NOTHING
+RETURNING *;
\ No newline at end of file
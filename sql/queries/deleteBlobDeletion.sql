@@ -0,0 +1,3 @@
+-- name: DeleteBlobDeletion :exec
+DELETE FROM blob_deletions
+WHERE blob_key = $1;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- name: DeleteBlock :exec
+DELETE FROM blocks
+WHERE blocker_id = $1 AND blocked_id = $2;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- name: DeleteBookmark :exec
+DELETE FROM bookmarks
+WHERE user_id = $1 AND chirp_id = $2;
\ No newline at end of file
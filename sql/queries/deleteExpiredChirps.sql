@@ -0,0 +1,8 @@
+-- name: DeleteExpiredChirps :execrows
+DELETE FROM chirps
+WHERE id IN (
+    SELECT id FROM chirps AS expired
+    WHERE expired.expires_at <= NOW()
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+);
\ No newline at end of file
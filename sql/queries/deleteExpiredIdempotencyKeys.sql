@@ -0,0 +1,3 @@
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at <= NOW();
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- name: DeleteFollow :exec
+DELETE FROM follows
+WHERE follower_id = $1 AND followee_id = $2;
\ No newline at end of file
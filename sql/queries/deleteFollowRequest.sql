@@ -0,0 +1,3 @@
+-- name: DeleteFollowRequest :execrows
+DELETE FROM follows
+WHERE follower_id = $1 AND followee_id = $2 AND status = 'pending';
\ No newline at end of file
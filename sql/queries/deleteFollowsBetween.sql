@@ -0,0 +1,4 @@
+-- name: DeleteFollowsBetween :exec
+DELETE FROM follows
+WHERE (follower_id = sqlc.arg(first_user_id) AND followee_id = sqlc.arg(second_user_id))
+OR (follower_id = sqlc.arg(second_user_id) AND followee_id = sqlc.arg(first_user_id));
\ No newline at end of file
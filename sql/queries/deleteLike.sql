@@ -0,0 +1,3 @@
+-- name: DeleteLike :exec
+DELETE FROM likes
+WHERE user_id = $1 AND chirp_id = $2;
@@ -0,0 +1,3 @@
+-- name: DeleteListMember :exec
+DELETE FROM list_members
+WHERE list_id = $1 AND user_id = $2;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- name: DeleteMediaItem :exec
+DELETE FROM media_items
+WHERE id = $1;
\ No newline at end of file
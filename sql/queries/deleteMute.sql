@@ -0,0 +1,3 @@
+-- name: DeleteMute :exec
+DELETE FROM mutes
+WHERE muter_id = $1 AND muted_id = $2;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- name: DeletePinnedChirp :exec
+DELETE FROM pinned_chirps
+WHERE user_id = $1 AND chirp_id = $2;
\ No newline at end of file
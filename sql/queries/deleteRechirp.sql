@@ -0,0 +1,3 @@
+-- name: DeleteRechirp :exec
+DELETE FROM chirps
+WHERE user_id = $1 AND rechirp_of_id = $2;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- name: DeleteTimelineBackfill :exec
+DELETE FROM timeline_backfills
+WHERE follower_id = $1 AND followee_id = $2;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- name: DeleteTrendingChirps :exec
+DELETE FROM trending_chirps
+WHERE period = $1;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- name: DeleteTrendingHashtags :exec
+DELETE FROM trending_hashtags
+WHERE period = $1;
\ No newline at end of file
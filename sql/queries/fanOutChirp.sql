@@ -0,0 +1,11 @@
+-- name: FanOutChirp :exec
+WITH fanned_out_chirp AS (
+    UPDATE chirps SET fanned_out = true
+    FROM users
+    WHERE chirps.id = sqlc.arg(chirp_id)::uuid AND users.id = chirps.user_id AND users.follower_count <= sqlc.arg(fanout_limit)::integer
+    RETURNING chirps.id, chirps.user_id, chirps.created_at
+)
+INSERT INTO timeline_entries (user_id, chirp_id, author_id, created_at)
+SELECT follows.follower_id, fanned_out_chirp.id, fanned_out_chirp.user_id, fanned_out_chirp.created_at FROM fanned_out_chirp
+JOIN follows ON follows.followee_id = fanned_out_chirp.user_id AND follows.status = 'accepted'
+ON CONFLICT (user_id, chirp_id) DO NOTHING;
\ No newline at end of file
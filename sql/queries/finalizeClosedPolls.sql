@@ -0,0 +1,4 @@
+-- name: FinalizeClosedPolls :execrows
+UPDATE polls
+SET finalized_at = NOW()
+WHERE finalized_at IS NULL AND closes_at <= NOW();
\ No newline at end of file
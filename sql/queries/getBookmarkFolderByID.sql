@@ -0,0 +1,3 @@
+-- name: GetBookmarkFolderByID :one
+SELECT * FROM bookmark_folders
+WHERE id = $1 AND user_id = $2;
\ No newline at end of file
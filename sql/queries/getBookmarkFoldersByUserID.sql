@@ -0,0 +1,4 @@
+-- name: GetBookmarkFoldersByUserID :many
+SELECT * FROM bookmark_folders
+WHERE user_id = $1
+ORDER BY name;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- name: GetBookmarkedChirps :many
+SELECT chirps.* FROM bookmarks
+JOIN chirps ON chirps.id = bookmarks.chirp_id
+WHERE bookmarks.user_id = sqlc.arg(user_id)
+AND (sqlc.narg(folder_id)::uuid IS NULL OR bookmarks.folder_id = sqlc.narg(folder_id)::uuid)
+AND chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+AND chirp_visible_to(chirps.user_id, chirps.visibility, sqlc.arg(user_id)::uuid)
+ORDER BY bookmarks.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
\ No newline at end of file
@@ -1,3 +1,3 @@
 -- name: GetChirpByID :one
 SELECT * FROM chirps
-WHERE id = $1;
\ No newline at end of file
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW()) AND chirp_visible_to(user_id, visibility, sqlc.narg(viewer_id)::uuid);
\ No newline at end of file
@@ -1,4 +1,9 @@
 -- name: GetChirpsByAuthorID :many
 SELECT * FROM chirps
-WHERE user_id = $1
-ORDER BY created_at ASC;
\ No newline at end of file
+WHERE user_id = sqlc.arg(user_id) AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW()) AND chirp_listed_for(user_id, visibility, sqlc.narg(viewer_id)::uuid) AND rechirp_listed_for(rechirp_of_id, sqlc.narg(viewer_id)::uuid)
+AND NOT EXISTS (SELECT 1 FROM pinned_chirps WHERE pinned_chirps.chirp_id = chirps.id)
+ORDER BY
+    CASE WHEN sqlc.arg(newest_first)::boolean THEN created_at END DESC,
+    CASE WHEN NOT sqlc.arg(newest_first)::boolean THEN created_at END ASC,
+    id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
\ No newline at end of file
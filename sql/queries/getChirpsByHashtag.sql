@@ -0,0 +1,11 @@
+-- name: GetChirpsByHashtag :many
+SELECT chirps.* FROM chirps
+JOIN chirp_hashtags ON chirp_hashtags.chirp_id = chirps.id
+JOIN hashtags ON hashtags.id = chirp_hashtags.hashtag_id
+WHERE hashtags.tag = sqlc.arg(tag) AND chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+AND chirp_listed_for(chirps.user_id, chirps.visibility, sqlc.narg(viewer_id)::uuid)
+ORDER BY
+    CASE WHEN sqlc.arg(newest_first)::boolean THEN chirps.created_at END DESC,
+    CASE WHEN NOT sqlc.arg(newest_first)::boolean THEN chirps.created_at END ASC,
+    chirps.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
\ No newline at end of file
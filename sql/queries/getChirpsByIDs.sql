@@ -0,0 +1,3 @@
+-- name: GetChirpsByIDs :many
+SELECT * FROM chirps
+WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW()) AND chirp_visible_to(user_id, visibility, sqlc.narg(viewer_id)::uuid);
\ No newline at end of file
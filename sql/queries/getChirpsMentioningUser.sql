@@ -0,0 +1,10 @@
+-- name: GetChirpsMentioningUser :many
+SELECT chirps.* FROM chirps
+JOIN mentions ON mentions.chirp_id = chirps.id
+WHERE mentions.user_id = sqlc.arg(user_id) AND chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+AND chirp_listed_for(chirps.user_id, chirps.visibility, sqlc.arg(user_id)::uuid)
+ORDER BY
+    CASE WHEN sqlc.arg(newest_first)::boolean THEN chirps.created_at END DESC,
+    CASE WHEN NOT sqlc.arg(newest_first)::boolean THEN chirps.created_at END ASC,
+    chirps.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
\ No newline at end of file
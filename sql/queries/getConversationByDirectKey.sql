@@ -0,0 +1,3 @@
+-- name: GetConversationByDirectKey :one
+SELECT * FROM conversations
+WHERE direct_key = $1;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- name: GetConversationForUser :one
+SELECT conversations.* FROM conversations
+JOIN conversation_participants ON conversation_participants.conversation_id = conversations.id
+WHERE conversations.id = sqlc.arg(id) AND conversation_participants.user_id = sqlc.arg(user_id);
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: GetConversationParticipants :many
+SELECT conversation_participants.conversation_id, conversation_participants.last_read_seq, users.id, users.handle FROM conversation_participants
+JOIN users ON users.id = conversation_participants.user_id
+WHERE conversation_participants.conversation_id = ANY(sqlc.arg(conversation_ids)::uuid[])
+ORDER BY conversation_participants.joined_at, users.id;
\ No newline at end of file
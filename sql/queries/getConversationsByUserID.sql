@@ -0,0 +1,6 @@
+-- name: GetConversationsByUserID :many
+SELECT conversations.* FROM conversations
+JOIN conversation_participants ON conversation_participants.conversation_id = conversations.id
+WHERE conversation_participants.user_id = sqlc.arg(user_id)
+ORDER BY COALESCE(conversations.last_message_at, conversations.created_at) DESC, conversations.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- name: GetDeletedChirpByID :one
+SELECT * FROM chirps
+WHERE id = $1 AND deleted_at IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- name: GetDeletedUserByEmail :one
+SELECT * FROM users
+WHERE email = $1 AND deleted_at IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: GetDraftsByUserID :many
+SELECT * FROM drafts
+WHERE user_id = $1
+ORDER BY updated_at DESC
+LIMIT $2 OFFSET $3;
\ No newline at end of file
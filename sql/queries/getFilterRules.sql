@@ -0,0 +1,3 @@
+-- name: GetFilterRules :many
+SELECT * FROM filter_rules
+ORDER BY word;
\ No newline at end of file
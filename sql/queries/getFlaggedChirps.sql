@@ -0,0 +1,7 @@
+-- name: GetFlaggedChirps :many
+SELECT chirps.*, chirp_flags.created_at AS flagged_at, chirp_flags.words
+FROM chirps
+JOIN chirp_flags ON chirp_flags.chirp_id = chirps.id
+WHERE chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+ORDER BY chirp_flags.created_at DESC
+LIMIT $1 OFFSET $2;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- name: GetFollowRequests :many
+SELECT users.id, users.handle, follows.created_at FROM follows
+JOIN users ON users.id = follows.follower_id
+WHERE follows.followee_id = sqlc.arg(user_id) AND follows.status = 'pending' AND users.deleted_at IS NULL
+ORDER BY follows.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- name: GetLikesByChirpID :many
+SELECT * FROM likes
+WHERE chirp_id = $1
+ORDER BY created_at DESC;
@@ -0,0 +1,3 @@
+-- name: GetListByID :one
+SELECT * FROM lists
+WHERE id = sqlc.arg(id) AND (NOT is_private OR owner_id = sqlc.narg(viewer_id)::uuid);
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- name: GetListChirps :many
+SELECT chirps.* FROM chirps
+JOIN list_members ON list_members.user_id = chirps.user_id
+WHERE list_members.list_id = sqlc.arg(list_id) AND chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+AND chirp_listed_for(chirps.user_id, chirps.visibility, sqlc.narg(viewer_id)::uuid) AND rechirp_listed_for(chirps.rechirp_of_id, sqlc.narg(viewer_id)::uuid)
+ORDER BY
+    CASE WHEN sqlc.arg(newest_first)::boolean THEN chirps.created_at END DESC,
+    CASE WHEN NOT sqlc.arg(newest_first)::boolean THEN chirps.created_at END ASC,
+    chirps.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
\ No newline at end of file
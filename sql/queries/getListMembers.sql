@@ -0,0 +1,7 @@
+-- name: GetListMembers :many
+SELECT users.id, users.handle, list_members.created_at FROM list_members
+JOIN users ON users.id = list_members.user_id
+WHERE list_members.list_id = sqlc.arg(list_id) AND users.deleted_at IS NULL
+AND NOT users_blocked(users.id, sqlc.narg(viewer_id)::uuid)
+ORDER BY list_members.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
\ No newline at end of file
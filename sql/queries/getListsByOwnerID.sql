@@ -0,0 +1,5 @@
+-- name: GetListsByOwnerID :many
+SELECT * FROM lists
+WHERE owner_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
\ No newline at end of file
@@ -0,0 +1,18 @@
+-- name: GetMediaAccess :one
+SELECT EXISTS (
+    SELECT 1 FROM media_items
+    LEFT JOIN chirps ON chirps.id = media_items.chirp_id
+    WHERE (media_items.blob_key = sqlc.arg(blob_key)::text OR media_items.thumbnail_key = sqlc.arg(blob_key)::text)
+        AND (media_items.chirp_id IS NULL OR (
+            chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+            AND chirp_visible_to(chirps.user_id, chirps.visibility, sqlc.narg(viewer_id)::uuid)
+        ))
+) AS visible, EXISTS (
+    SELECT 1 FROM media_items
+    LEFT JOIN chirps ON chirps.id = media_items.chirp_id
+    WHERE (media_items.blob_key = sqlc.arg(blob_key)::text OR media_items.thumbnail_key = sqlc.arg(blob_key)::text)
+        AND (media_items.chirp_id IS NULL OR (
+            chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+            AND chirp_visible_to(chirps.user_id, chirps.visibility, NULL::uuid)
+        ))
+) AS public;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- name: GetMediaExpiry :one
+SELECT chirps.expires_at FROM media_items
+JOIN chirps ON chirps.id = media_items.chirp_id
+WHERE (media_items.blob_key = sqlc.arg(blob_key)::text OR media_items.thumbnail_key = sqlc.arg(blob_key)::text) AND chirps.expires_at IS NOT NULL
+ORDER BY chirps.expires_at
+LIMIT 1;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- name: GetMediaItemsByChirpIDs :many
+SELECT * FROM media_items
+WHERE chirp_id = ANY(sqlc.arg(chirp_ids)::uuid[])
+ORDER BY chirp_id, position;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- name: GetMentionsByChirpIDs :many
+SELECT * FROM mentions
+WHERE chirp_id = ANY(sqlc.arg(chirp_ids)::uuid[]);
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- name: GetMessageRecipients :many
+SELECT users.id, users.dms_from_following_only,
+    users_blocked(users.id, sqlc.arg(sender_id)::uuid)::boolean AS blocked,
+    EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = users.id AND follows.followee_id = sqlc.arg(sender_id)::uuid AND follows.status = 'accepted'
+    ) AS follows_sender
+FROM users
+WHERE users.id = ANY(sqlc.arg(user_ids)::uuid[]) AND users.deleted_at IS NULL;
\ No newline at end of file
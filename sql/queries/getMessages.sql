@@ -0,0 +1,7 @@
+-- name: GetMessages :many
+SELECT messages.* FROM messages
+WHERE messages.conversation_id = sqlc.arg(conversation_id)
+AND NOT users_blocked(messages.sender_id, sqlc.arg(viewer_id)::uuid)
+AND (sqlc.narg(before_seq)::bigint IS NULL OR messages.seq < sqlc.narg(before_seq)::bigint)
+ORDER BY messages.seq DESC
+LIMIT sqlc.arg('limit');
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- name: GetNotificationActors :many
+SELECT notification_actors.notification_id, users.id, users.handle FROM notification_actors
+JOIN users ON users.id = notification_actors.actor_id
+WHERE notification_actors.notification_id = ANY(sqlc.arg(notification_ids)::uuid[]) AND users.deleted_at IS NULL
+AND notification_actors.actor_id IN (
+    SELECT recent.actor_id FROM notification_actors AS recent
+    WHERE recent.notification_id = notification_actors.notification_id
+    ORDER BY recent.created_at DESC
+    LIMIT sqlc.arg(per_notification)
+)
+ORDER BY notification_actors.created_at DESC;
\ No newline at end of file
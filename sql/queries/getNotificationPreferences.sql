@@ -0,0 +1,3 @@
+-- name: GetNotificationPreferences :many
+SELECT * FROM notification_preferences
+WHERE user_id = $1;
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- name: GetNotifications :many
+SELECT notifications.* FROM notifications
+LEFT JOIN chirps ON chirps.id = notifications.chirp_id
+WHERE notifications.user_id = sqlc.arg(user_id)
+AND (notifications.chirp_id IS NULL OR (chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW()) AND chirp_visible_to(chirps.user_id, chirps.visibility, sqlc.arg(user_id)::uuid)))
+AND (sqlc.narg(before_seq)::bigint IS NULL OR notifications.seq < sqlc.narg(before_seq)::bigint)
+ORDER BY notifications.seq DESC
+LIMIT sqlc.arg('limit');
\ No newline at end of file
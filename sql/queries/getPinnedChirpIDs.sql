@@ -0,0 +1,4 @@
+-- name: GetPinnedChirpIDs :many
+SELECT pinned_chirps.chirp_id FROM pinned_chirps
+JOIN chirps ON chirps.id = pinned_chirps.chirp_id
+WHERE pinned_chirps.user_id = $1 AND chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW());
\ No newline at end of file
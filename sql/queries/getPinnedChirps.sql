@@ -0,0 +1,6 @@
+-- name: GetPinnedChirps :many
+SELECT chirps.* FROM pinned_chirps
+JOIN chirps ON chirps.id = pinned_chirps.chirp_id
+WHERE pinned_chirps.user_id = sqlc.arg(user_id) AND chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+AND chirp_listed_for(chirps.user_id, chirps.visibility, sqlc.narg(viewer_id)::uuid) AND rechirp_listed_for(chirps.rechirp_of_id, sqlc.narg(viewer_id)::uuid)
+ORDER BY pinned_chirps.pinned_at DESC;
\ No newline at end of file
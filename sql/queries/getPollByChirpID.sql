@@ -0,0 +1,5 @@
+-- name: GetPollByChirpID :one
+SELECT polls.* FROM polls
+JOIN chirps ON chirps.id = polls.chirp_id
+WHERE polls.chirp_id = sqlc.arg(chirp_id) AND chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+AND chirp_visible_to(chirps.user_id, chirps.visibility, sqlc.narg(viewer_id)::uuid);
\ No newline at end of file
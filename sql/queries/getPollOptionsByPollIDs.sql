@@ -0,0 +1,4 @@
+-- name: GetPollOptionsByPollIDs :many
+SELECT * FROM poll_options
+WHERE poll_id = ANY(sqlc.arg(poll_ids)::uuid[])
+ORDER BY poll_id, position;
\ No newline at end of file
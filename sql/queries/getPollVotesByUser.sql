@@ -0,0 +1,3 @@
+-- name: GetPollVotesByUser :many
+SELECT * FROM poll_votes
+WHERE user_id = sqlc.arg(user_id) AND poll_id = ANY(sqlc.arg(poll_ids)::uuid[]);
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- name: GetPollsByChirpIDs :many
+SELECT * FROM polls
+WHERE chirp_id = ANY(sqlc.arg(chirp_ids)::uuid[]);
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: GetProfileByHandle :one
+SELECT users.*, media_items.blob_key AS avatar_key, media_items.thumbnail_key AS avatar_thumbnail_key FROM users
+LEFT JOIN media_items ON media_items.id = users.avatar_id
+WHERE LOWER(users.handle) = LOWER(sqlc.arg(handle)::text) AND users.deleted_at IS NULL
+AND NOT users_blocked(users.id, sqlc.narg(viewer_id)::uuid);
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- name: GetProfileByID :one
+SELECT users.*, media_items.blob_key AS avatar_key, media_items.thumbnail_key AS avatar_thumbnail_key FROM users
+LEFT JOIN media_items ON media_items.id = users.avatar_id
+WHERE users.id = $1 AND users.deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- name: GetRecentChirpsByAuthor :many
+SELECT * FROM chirps
+WHERE user_id = $1 AND rechirp_of_id IS NULL AND created_at > $2 AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+ORDER BY created_at DESC;
\ No newline at end of file
@@ -0,0 +1,34 @@
+-- name: GetTimeline :many
+SELECT chirps.* FROM (
+    (
+        SELECT timeline_entries.chirp_id, timeline_entries.created_at FROM timeline_entries
+        JOIN chirps ON chirps.id = timeline_entries.chirp_id
+        WHERE timeline_entries.user_id = sqlc.arg(user_id)::uuid
+        AND (sqlc.narg(before_created_at)::timestamp IS NULL OR (timeline_entries.created_at, timeline_entries.chirp_id) < (sqlc.narg(before_created_at)::timestamp, sqlc.narg(before_id)::uuid))
+        AND chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW()) AND chirp_listed_for(chirps.user_id, chirps.visibility, sqlc.arg(user_id)::uuid) AND rechirp_listed_for(chirps.rechirp_of_id, sqlc.arg(user_id)::uuid)
+        ORDER BY timeline_entries.created_at DESC, timeline_entries.chirp_id DESC
+        LIMIT sqlc.arg('limit')
+    )
+    UNION ALL
+    (
+        SELECT chirps.id, chirps.created_at FROM chirps
+        WHERE chirps.user_id = sqlc.arg(user_id)::uuid
+        AND (sqlc.narg(before_created_at)::timestamp IS NULL OR (chirps.created_at, chirps.id) < (sqlc.narg(before_created_at)::timestamp, sqlc.narg(before_id)::uuid))
+        AND chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW()) AND rechirp_listed_for(chirps.rechirp_of_id, sqlc.arg(user_id)::uuid)
+        ORDER BY chirps.created_at DESC, chirps.id DESC
+        LIMIT sqlc.arg('limit')
+    )
+    UNION ALL
+    (
+        SELECT chirps.id, chirps.created_at FROM follows
+        JOIN chirps ON chirps.user_id = follows.followee_id AND NOT chirps.fanned_out
+        WHERE follows.follower_id = sqlc.arg(user_id)::uuid AND follows.status = 'accepted'
+        AND (sqlc.narg(before_created_at)::timestamp IS NULL OR (chirps.created_at, chirps.id) < (sqlc.narg(before_created_at)::timestamp, sqlc.narg(before_id)::uuid))
+        AND chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW()) AND chirp_listed_for(chirps.user_id, chirps.visibility, sqlc.arg(user_id)::uuid) AND rechirp_listed_for(chirps.rechirp_of_id, sqlc.arg(user_id)::uuid)
+        ORDER BY chirps.created_at DESC, chirps.id DESC
+        LIMIT sqlc.arg('limit')
+    )
+) AS page
+JOIN chirps ON chirps.id = page.chirp_id
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg('limit');
\ No newline at end of file
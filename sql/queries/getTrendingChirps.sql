@@ -0,0 +1,7 @@
+-- name: GetTrendingChirps :many
+SELECT chirps.* FROM trending_chirps
+JOIN chirps ON chirps.id = trending_chirps.chirp_id
+WHERE trending_chirps.period = sqlc.arg(period) AND chirps.deleted_at IS NULL AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+AND chirp_listed_for(chirps.user_id, chirps.visibility, sqlc.narg(viewer_id)::uuid) AND rechirp_listed_for(chirps.rechirp_of_id, sqlc.narg(viewer_id)::uuid)
+ORDER BY trending_chirps.score DESC
+LIMIT sqlc.arg('limit');
\ No newline at end of file
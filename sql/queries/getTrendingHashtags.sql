@@ -0,0 +1,5 @@
+-- name: GetTrendingHashtags :many
+SELECT * FROM trending_hashtags
+WHERE period = $1
+ORDER BY score DESC
+LIMIT $2;
\ No newline at end of file
@@ -1,3 +1,3 @@
 -- name: GetUserByEmail :one
 SELECT * FROM users
-WHERE email = $1;
\ No newline at end of file
+WHERE email = $1 AND deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- name: GetUsersByHandles :many
+SELECT id, handle FROM users
+WHERE deleted_at IS NULL AND LOWER(handle) = ANY(sqlc.arg(handles)::text[])
+AND NOT users_blocked(id, sqlc.arg(author_id)::uuid);
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: IsBlobReferenced :one
+SELECT EXISTS (
+    SELECT 1 FROM media_items
+    WHERE media_items.blob_key = sqlc.arg(blob_key)::text OR media_items.thumbnail_key = sqlc.arg(blob_key)::text
+) AS referenced;
\ No newline at end of file
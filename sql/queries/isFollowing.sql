@@ -0,0 +1,5 @@
+-- name: IsFollowing :one
+SELECT EXISTS (
+    SELECT 1 FROM follows
+    WHERE follower_id = $1 AND followee_id = $2 AND status = 'accepted'
+);
\ No newline at end of file
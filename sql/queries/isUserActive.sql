@@ -0,0 +1,5 @@
+-- name: IsUserActive :one
+SELECT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = sqlc.arg(id)::uuid AND users.deleted_at IS NULL
+) AS active;
\ No newline at end of file
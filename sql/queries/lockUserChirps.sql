@@ -0,0 +1,2 @@
+-- name: LockUserChirps :exec
+SELECT pg_advisory_xact_lock(hashtext(sqlc.arg(user_id)::uuid::text));
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- name: MarkConversationRead :exec
+UPDATE conversation_participants
+SET last_read_seq = GREATEST(last_read_seq, sqlc.arg(seq)::bigint)
+WHERE conversation_id = sqlc.arg(conversation_id) AND user_id = sqlc.arg(user_id);
\ No newline at end of file
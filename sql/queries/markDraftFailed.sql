@@ -0,0 +1,5 @@
+-- name: MarkDraftFailed :exec
+UPDATE drafts
+SET failed_at = NOW(),
+    failure_reason = $2
+WHERE id = $1;
\ No newline at end of file
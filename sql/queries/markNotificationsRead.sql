@@ -0,0 +1,4 @@
+-- name: MarkNotificationsRead :exec
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = $1 AND read_at IS NULL AND seq <= $2;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at <= NOW() - make_interval(secs => sqlc.arg(restore_window_seconds)::double precision);
\ No newline at end of file
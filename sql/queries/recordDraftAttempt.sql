@@ -0,0 +1,5 @@
+-- name: RecordDraftAttempt :exec
+UPDATE drafts
+SET attempts = attempts + 1,
+    retry_at = NOW() + make_interval(secs => sqlc.arg(retry_in_seconds)::double precision)
+WHERE id = sqlc.arg(id);
\ No newline at end of file
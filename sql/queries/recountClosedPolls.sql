@@ -0,0 +1,10 @@
+-- name: RecountClosedPolls :exec
+UPDATE poll_options
+SET vote_count = (
+    SELECT COUNT(*) FROM poll_votes
+    WHERE poll_votes.option_id = poll_options.id
+)
+WHERE poll_id IN (
+    SELECT id FROM polls
+    WHERE finalized_at IS NULL AND closes_at <= NOW()
+);
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- name: RestoreChirp :exec
+UPDATE chirps
+SET deleted_at = NULL
+WHERE (id = sqlc.arg(id) OR rechirp_of_id = sqlc.arg(id)) AND deleted_at = sqlc.arg(deleted_at)::timestamp;
\ No newline at end of file
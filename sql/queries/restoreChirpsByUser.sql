@@ -0,0 +1,5 @@
+-- name: RestoreChirpsByUser :exec
+UPDATE chirps
+SET deleted_at = NULL
+WHERE (user_id = sqlc.arg(user_id) OR rechirp_of_id IN (SELECT id FROM chirps AS own WHERE own.user_id = sqlc.arg(user_id)))
+AND deleted_at = sqlc.arg(deleted_at)::timestamp;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- name: SaveIdempotentResponse :exec
+UPDATE idempotency_keys
+SET response_status = $3,
+    response_content_type = $4,
+    response_body = $5
+WHERE scope = $1 AND key = $2;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: SetUserAvatar :exec
+UPDATE users
+SET updated_at = NOW(),
+    avatar_id = $2
+WHERE id = $1;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- name: SetUserHandle :one
+UPDATE users
+SET updated_at = NOW(),
+    handle = $2
+WHERE id = $1
+RETURNING *;
\ No newline at end of file
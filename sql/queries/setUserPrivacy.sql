@@ -0,0 +1,6 @@
+-- name: SetUserPrivacy :one
+UPDATE users
+SET updated_at = NOW(),
+    is_private = $2
+WHERE id = $1
+RETURNING *;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- name: SoftDeleteChirp :exec
+WITH deleted_chirps AS (
+    UPDATE chirps
+    SET deleted_at = NOW()
+    WHERE (id = sqlc.arg(id)::uuid OR rechirp_of_id = sqlc.arg(id)::uuid) AND deleted_at IS NULL
+    RETURNING id
+)
+DELETE FROM pinned_chirps
+WHERE chirp_id IN (SELECT id FROM deleted_chirps);
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: SoftDeleteChirpsByUser :exec
+UPDATE chirps
+SET deleted_at = NOW()
+WHERE (user_id = $1 OR rechirp_of_id IN (SELECT id FROM chirps AS own WHERE own.user_id = $1))
+AND deleted_at IS NULL;
\ No newline at end of file
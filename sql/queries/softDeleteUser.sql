@@ -0,0 +1,5 @@
+-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL;
\ No newline at end of file
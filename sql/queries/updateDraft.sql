@@ -0,0 +1,17 @@
+-- name: UpdateDraft :one
+UPDATE drafts
+SET updated_at = NOW(),
+    body = $3,
+    quoted_chirp_id = $4,
+    media_ids = $5,
+    poll_options = $6,
+    poll_closes_at = $7,
+    publish_at = $8,
+    visibility = $9,
+    expires_in = $10,
+    failed_at = NULL,
+    failure_reason = NULL,
+    attempts = 0,
+    retry_at = NULL
+WHERE id = $1 AND user_id = $2
+RETURNING *;
\ No newline at end of file
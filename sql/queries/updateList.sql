@@ -0,0 +1,8 @@
+-- name: UpdateList :one
+UPDATE lists
+SET updated_at = NOW(),
+    name = $3,
+    description = $4,
+    is_private = $5
+WHERE id = $1 AND owner_id = $2
+RETURNING *;
\ No newline at end of file
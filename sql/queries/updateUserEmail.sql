@@ -0,0 +1,6 @@
+-- name: UpdateUserEmail :one
+UPDATE users
+SET updated_at = NOW(),
+    email = $2
+WHERE id = $1
+RETURNING *;
\ No newline at end of file
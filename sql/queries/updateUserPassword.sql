@@ -0,0 +1,5 @@
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET updated_at = NOW(),
+    hashed_password = $2
+WHERE id = $1;
\ No newline at end of file
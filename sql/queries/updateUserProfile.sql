@@ -0,0 +1,12 @@
+-- name: UpdateUserProfile :one
+UPDATE users
+SET updated_at = NOW(),
+    handle = CASE WHEN sqlc.arg(clear_handle)::boolean THEN NULL ELSE COALESCE(sqlc.narg(handle)::text, handle) END,
+    display_name = COALESCE(sqlc.narg(display_name)::text, display_name),
+    bio = COALESCE(sqlc.narg(bio)::text, bio),
+    location = COALESCE(sqlc.narg(location)::text, location),
+    website = COALESCE(sqlc.narg(website)::text, website),
+    is_private = COALESCE(sqlc.narg(is_private)::boolean, is_private),
+    dms_from_following_only = COALESCE(sqlc.narg(dms_from_following_only)::boolean, dms_from_following_only)
+WHERE id = sqlc.arg(id)
+RETURNING *;
\ No newline at end of file
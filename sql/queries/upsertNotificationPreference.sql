@@ -0,0 +1,8 @@
+-- name: UpsertNotificationPreference :exec
+INSERT INTO notification_preferences (user_id, type, enabled)
+VALUES (
+    $1,
+    $2,
+    $3
+)
+ON CONFLICT (user_id, type) DO UPDATE SET enabled = EXCLUDED.enabled;
\ No newline at end of file
@@ -0,0 +1,47 @@
+-- +goose Up
+CREATE TABLE likes (
+    user_id UUID NOT NULL,
+    chirp_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (user_id, chirp_id),
+
+    CONSTRAINT fk_likes_users
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_likes_chirps
+    FOREIGN KEY (chirp_id) REFERENCES chirps(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_likes_chirp_id ON likes (chirp_id, created_at);
+
+ALTER TABLE chirps
+ADD COLUMN like_count INTEGER NOT NULL DEFAULT 0;
+
+-- +goose StatementBegin
+CREATE FUNCTION update_chirp_like_count() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE chirps SET like_count = like_count + 1 WHERE id = NEW.chirp_id;
+    ELSIF TG_OP = 'DELETE' THEN
+        UPDATE chirps SET like_count = like_count - 1 WHERE id = OLD.chirp_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_likes_count
+AFTER INSERT OR DELETE ON likes
+FOR EACH ROW EXECUTE FUNCTION update_chirp_like_count();
+
+-- +goose Down
+DROP TRIGGER trg_likes_count ON likes;
+DROP FUNCTION update_chirp_like_count;
+
+ALTER TABLE chirps
+DROP COLUMN like_count;
+
+DROP TABLE likes;
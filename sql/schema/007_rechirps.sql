@@ -0,0 +1,35 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN rechirp_of_id UUID,
+ADD COLUMN quoted_chirp_id UUID,
+ADD CONSTRAINT fk_chirps_rechirp_of
+    FOREIGN KEY (rechirp_of_id) REFERENCES chirps(id)
+    ON DELETE CASCADE,
+ADD CONSTRAINT fk_chirps_quoted_chirp
+    FOREIGN KEY (quoted_chirp_id) REFERENCES chirps(id)
+    ON DELETE SET NULL;
+
+-- Rechirps carry no body of their own, so only original chirps need unique bodies.
+ALTER TABLE chirps
+DROP CONSTRAINT chirps_body_key;
+
+CREATE UNIQUE INDEX chirps_body_key ON chirps (body) WHERE rechirp_of_id IS NULL;
+
+CREATE UNIQUE INDEX idx_chirps_user_rechirp ON chirps (user_id, rechirp_of_id) WHERE rechirp_of_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX idx_chirps_user_rechirp;
+
+DELETE FROM chirps
+WHERE rechirp_of_id IS NOT NULL;
+
+DROP INDEX chirps_body_key;
+
+ALTER TABLE chirps
+ADD CONSTRAINT chirps_body_key UNIQUE (body);
+
+ALTER TABLE chirps
+DROP CONSTRAINT fk_chirps_quoted_chirp,
+DROP CONSTRAINT fk_chirps_rechirp_of,
+DROP COLUMN quoted_chirp_id,
+DROP COLUMN rechirp_of_id;
\ No newline at end of file
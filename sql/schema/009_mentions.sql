@@ -0,0 +1,32 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN handle TEXT;
+
+CREATE UNIQUE INDEX idx_users_handle ON users (LOWER(handle));
+
+CREATE TABLE mentions (
+    chirp_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    handle TEXT NOT NULL,
+
+    PRIMARY KEY (chirp_id, user_id),
+
+    CONSTRAINT fk_mentions_chirps
+    FOREIGN KEY (chirp_id) REFERENCES chirps(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_mentions_users
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_mentions_user_id ON mentions (user_id, created_at);
+
+-- +goose Down
+DROP TABLE mentions;
+
+DROP INDEX idx_users_handle;
+
+ALTER TABLE users
+DROP COLUMN handle;
\ No newline at end of file
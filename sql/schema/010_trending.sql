@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE trending_hashtags (
+    period TEXT NOT NULL,
+    tag TEXT NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    computed_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (period, tag)
+);
+
+CREATE TABLE trending_chirps (
+    period TEXT NOT NULL,
+    chirp_id UUID NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    computed_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (period, chirp_id),
+
+    CONSTRAINT fk_trending_chirps_chirps
+    FOREIGN KEY (chirp_id) REFERENCES chirps(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_chirps_created_at ON chirps (created_at);
+CREATE INDEX idx_likes_created_at ON likes (created_at);
+
+-- +goose Down
+DROP INDEX idx_likes_created_at;
+DROP INDEX idx_chirps_created_at;
+
+DROP TABLE trending_chirps;
+DROP TABLE trending_hashtags;
\ No newline at end of file
@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE media_items (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL,
+    chirp_id UUID,
+    position INTEGER NOT NULL DEFAULT 0,
+    content_type TEXT NOT NULL,
+    blob_key TEXT NOT NULL,
+    thumbnail_key TEXT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    size_bytes BIGINT NOT NULL,
+
+    CONSTRAINT fk_media_items_users
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_media_items_chirps
+    FOREIGN KEY (chirp_id) REFERENCES chirps(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_media_items_chirp_id ON media_items (chirp_id, position);
+
+-- +goose Down
+DROP TABLE media_items;
\ No newline at end of file
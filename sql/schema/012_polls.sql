@@ -0,0 +1,73 @@
+-- +goose Up
+CREATE TABLE polls (
+    id UUID PRIMARY KEY,
+    chirp_id UUID NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL,
+    closes_at TIMESTAMP NOT NULL,
+    finalized_at TIMESTAMP,
+
+    CONSTRAINT fk_polls_chirps
+    FOREIGN KEY (chirp_id) REFERENCES chirps(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_polls_open ON polls (closes_at) WHERE finalized_at IS NULL;
+
+CREATE TABLE poll_options (
+    id UUID PRIMARY KEY,
+    poll_id UUID NOT NULL,
+    position INTEGER NOT NULL,
+    label TEXT NOT NULL,
+    vote_count INTEGER NOT NULL DEFAULT 0,
+
+    UNIQUE (poll_id, position),
+    UNIQUE (id, poll_id),
+
+    CONSTRAINT fk_poll_options_polls
+    FOREIGN KEY (poll_id) REFERENCES polls(id)
+    ON DELETE CASCADE
+);
+
+-- The primary key allows a single vote per user and poll, and the composite
+-- foreign key guarantees the option belongs to that poll.
+CREATE TABLE poll_votes (
+    poll_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    option_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (poll_id, user_id),
+
+    CONSTRAINT fk_poll_votes_poll_options
+    FOREIGN KEY (option_id, poll_id) REFERENCES poll_options(id, poll_id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_poll_votes_users
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+-- +goose StatementBegin
+CREATE FUNCTION update_poll_option_vote_count() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE poll_options SET vote_count = vote_count + 1 WHERE id = NEW.option_id;
+    ELSIF TG_OP = 'DELETE' THEN
+        UPDATE poll_options SET vote_count = vote_count - 1 WHERE id = OLD.option_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_poll_votes_count
+AFTER INSERT OR DELETE ON poll_votes
+FOR EACH ROW EXECUTE FUNCTION update_poll_option_vote_count();
+
+-- +goose Down
+DROP TRIGGER trg_poll_votes_count ON poll_votes;
+DROP FUNCTION update_poll_option_vote_count;
+
+DROP TABLE poll_votes;
+DROP TABLE poll_options;
+DROP TABLE polls;
\ No newline at end of file
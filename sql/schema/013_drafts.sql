@@ -0,0 +1,32 @@
+-- +goose Up
+-- A draft holds everything needed to post a chirp later. Setting publish_at
+-- schedules it; the scheduler deletes the draft once the chirp is published,
+-- or records why publishing failed.
+CREATE TABLE drafts (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL,
+    body TEXT NOT NULL,
+    quoted_chirp_id UUID,
+    media_ids UUID[] NOT NULL DEFAULT '{}',
+    poll_options TEXT[] NOT NULL DEFAULT '{}',
+    poll_closes_at TIMESTAMP,
+    publish_at TIMESTAMP,
+    failed_at TIMESTAMP,
+    failure_reason TEXT,
+
+    CONSTRAINT fk_drafts_users
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_drafts_quoted_chirps
+    FOREIGN KEY (quoted_chirp_id) REFERENCES chirps(id)
+    ON DELETE SET NULL
+);
+
+CREATE INDEX idx_drafts_user_id ON drafts (user_id, updated_at DESC);
+CREATE INDEX idx_drafts_due ON drafts (publish_at) WHERE publish_at IS NOT NULL AND failed_at IS NULL;
+
+-- +goose Down
+DROP TABLE drafts;
\ No newline at end of file
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE filter_rules (
+    word TEXT PRIMARY KEY,
+    action TEXT NOT NULL DEFAULT 'mask' CHECK (action IN ('mask', 'reject', 'flag')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO filter_rules (word, action)
+VALUES ('kerfuffle', 'mask'), ('sharbert', 'mask'), ('fornax', 'mask');
+
+CREATE TABLE chirp_flags (
+    chirp_id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    words TEXT[] NOT NULL,
+
+    CONSTRAINT fk_chirp_flags_chirps
+    FOREIGN KEY (chirp_id) REFERENCES chirps(id)
+    ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE chirp_flags;
+DROP TABLE filter_rules;
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Different users may post the same body; repeated posts by one author are
+-- caught by the duplicate window in the application instead.
+DROP INDEX chirps_body_key;
+
+CREATE INDEX idx_chirps_user_created_at ON chirps (user_id, created_at DESC);
+
+-- +goose Down
+DROP INDEX idx_chirps_user_created_at;
+
+CREATE UNIQUE INDEX chirps_body_key ON chirps (body) WHERE rechirp_of_id IS NULL;
\ No newline at end of file
@@ -0,0 +1,21 @@
+-- +goose Up
+-- scope identifies the endpoint and the credentials a key was used with, so
+-- that two clients picking the same key don't see each other's responses.
+-- The response columns stay NULL while the first request is being handled.
+CREATE TABLE idempotency_keys (
+    scope TEXT NOT NULL,
+    key TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    response_status INTEGER,
+    response_content_type TEXT,
+    response_body BYTEA,
+
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+-- +goose Down
+DROP TABLE idempotency_keys;
\ No newline at end of file
@@ -0,0 +1,58 @@
+-- +goose Up
+-- Deleted chirps and accounts keep their rows, with deleted_at set, until the
+-- restore window has passed and the purge worker removes them for good.
+ALTER TABLE chirps
+ADD COLUMN deleted_at TIMESTAMP;
+
+ALTER TABLE users
+ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_chirps_deleted_at ON chirps (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_users_deleted_at ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Media rows disappear along with their chirps or users; blobs no longer
+-- referenced by any of them are queued here for the purge worker to delete
+-- from the blob store.
+CREATE TABLE blob_deletions (
+    blob_key TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- +goose StatementBegin
+CREATE FUNCTION queue_blob_deletions() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO blob_deletions (blob_key, created_at)
+        SELECT blob_key, NOW()
+        FROM (VALUES (OLD.blob_key), (OLD.thumbnail_key)) AS keys (blob_key)
+        WHERE NOT EXISTS (
+            SELECT 1 FROM media_items
+            WHERE media_items.blob_key = keys.blob_key OR media_items.thumbnail_key = keys.blob_key
+        )
+        ON CONFLICT (blob_key) DO NOTHING;
+    ELSIF TG_OP = 'INSERT' THEN
+        DELETE FROM blob_deletions
+        WHERE blob_key IN (NEW.blob_key, NEW.thumbnail_key);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_media_items_blob_deletions
+AFTER INSERT OR DELETE ON media_items
+FOR EACH ROW EXECUTE FUNCTION queue_blob_deletions();
+
+-- +goose Down
+DROP TRIGGER trg_media_items_blob_deletions ON media_items;
+DROP FUNCTION queue_blob_deletions;
+DROP TABLE blob_deletions;
+
+DROP INDEX idx_users_deleted_at;
+DROP INDEX idx_chirps_deleted_at;
+
+ALTER TABLE users
+DROP COLUMN deleted_at;
+
+ALTER TABLE chirps
+DROP COLUMN deleted_at;
\ No newline at end of file
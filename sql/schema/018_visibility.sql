@@ -0,0 +1,26 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'followers', 'private'));
+
+ALTER TABLE drafts
+ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'followers', 'private'));
+
+-- chirp_visible_to decides whether a viewer, NULL when anonymous, may read a
+-- chirp. Every query returning chirps to users filters on it, so the rule
+-- lives in one place. 020_follows.sql opens followers-only chirps to the
+-- author's followers.
+-- +goose StatementBegin
+CREATE FUNCTION chirp_visible_to(chirp_author_id UUID, chirp_visibility TEXT, viewer_id UUID) RETURNS BOOLEAN AS $$
+    SELECT chirp_visibility = 'public'
+        OR (viewer_id IS NOT NULL AND chirp_author_id = viewer_id);
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION chirp_visible_to;
+
+ALTER TABLE drafts
+DROP COLUMN visibility;
+
+ALTER TABLE chirps
+DROP COLUMN visibility;
\ No newline at end of file
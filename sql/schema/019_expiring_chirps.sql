@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN expires_at TIMESTAMP;
+
+CREATE INDEX idx_chirps_expires_at ON chirps (expires_at) WHERE expires_at IS NOT NULL;
+
+ALTER TABLE drafts
+ADD COLUMN expires_in INTEGER;
+
+-- +goose Down
+ALTER TABLE drafts
+DROP COLUMN expires_in;
+
+DROP INDEX idx_chirps_expires_at;
+
+ALTER TABLE chirps
+DROP COLUMN expires_at;
\ No newline at end of file
@@ -0,0 +1,81 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN is_private BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN follower_count INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN following_count INTEGER NOT NULL DEFAULT 0;
+
+-- Follows of private accounts start out pending until the followee accepts
+-- them. Only accepted follows count towards visibility and the counters.
+CREATE TABLE follows (
+    follower_id UUID NOT NULL,
+    followee_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    status TEXT NOT NULL DEFAULT 'accepted' CHECK (status IN ('pending', 'accepted')),
+
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id),
+
+    CONSTRAINT fk_follows_follower
+    FOREIGN KEY (follower_id) REFERENCES users(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_follows_followee
+    FOREIGN KEY (followee_id) REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_follows_follower_id ON follows (follower_id, created_at);
+CREATE INDEX idx_follows_followee_id_created_at ON follows (followee_id, created_at);
+
+-- +goose StatementBegin
+CREATE FUNCTION update_user_follow_counts() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('DELETE', 'UPDATE') AND OLD.status = 'accepted' THEN
+        UPDATE users SET follower_count = follower_count - 1 WHERE id = OLD.followee_id;
+        UPDATE users SET following_count = following_count - 1 WHERE id = OLD.follower_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.status = 'accepted' THEN
+        UPDATE users SET follower_count = follower_count + 1 WHERE id = NEW.followee_id;
+        UPDATE users SET following_count = following_count + 1 WHERE id = NEW.follower_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_follows_count
+AFTER INSERT OR DELETE OR UPDATE OF status ON follows
+FOR EACH ROW EXECUTE FUNCTION update_user_follow_counts();
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION chirp_visible_to(chirp_author_id UUID, chirp_visibility TEXT, viewer_id UUID) RETURNS BOOLEAN AS $$
+    SELECT chirp_visibility = 'public'
+        OR (viewer_id IS NOT NULL AND (
+            chirp_author_id = viewer_id
+            OR (chirp_visibility = 'followers' AND EXISTS (
+                SELECT 1 FROM follows
+                WHERE follows.follower_id = viewer_id
+                    AND follows.followee_id = chirp_author_id
+                    AND follows.status = 'accepted'
+            ))
+        ));
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION chirp_visible_to(chirp_author_id UUID, chirp_visibility TEXT, viewer_id UUID) RETURNS BOOLEAN AS $$
+    SELECT chirp_visibility = 'public'
+        OR (viewer_id IS NOT NULL AND chirp_author_id = viewer_id);
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+DROP TRIGGER trg_follows_count ON follows;
+DROP FUNCTION update_user_follow_counts;
+
+DROP TABLE follows;
+
+ALTER TABLE users
+DROP COLUMN following_count,
+DROP COLUMN follower_count,
+DROP COLUMN is_private;
\ No newline at end of file
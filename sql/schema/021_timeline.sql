@@ -0,0 +1,71 @@
+-- +goose Up
+-- timeline_entries holds each user's home timeline, filled in when chirps are
+-- posted (fan-out on write). Chirps of accounts with too many followers are
+-- not copied and are merged in when the timeline is read instead.
+CREATE TABLE timeline_entries (
+    user_id UUID NOT NULL,
+    chirp_id UUID NOT NULL,
+    author_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (user_id, chirp_id),
+
+    CONSTRAINT fk_timeline_entries_users
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_timeline_entries_chirps
+    FOREIGN KEY (chirp_id) REFERENCES chirps(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_timeline_entries_user_created_at ON timeline_entries (user_id, created_at DESC, chirp_id DESC);
+CREATE INDEX idx_timeline_entries_user_author ON timeline_entries (user_id, author_id);
+
+-- timeline_backfills queues follows whose earlier chirps still have to be
+-- copied into the follower's timeline.
+CREATE TABLE timeline_backfills (
+    follower_id UUID NOT NULL,
+    followee_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (follower_id, followee_id),
+
+    CONSTRAINT fk_timeline_backfills_follower
+    FOREIGN KEY (follower_id) REFERENCES users(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_timeline_backfills_followee
+    FOREIGN KEY (followee_id) REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+-- +goose StatementBegin
+CREATE FUNCTION sync_timeline_follows() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        DELETE FROM timeline_backfills WHERE follower_id = OLD.follower_id AND followee_id = OLD.followee_id;
+        DELETE FROM timeline_entries WHERE user_id = OLD.follower_id AND author_id = OLD.followee_id;
+    ELSIF NEW.status = 'accepted' AND (TG_OP = 'INSERT' OR OLD.status <> 'accepted') THEN
+        INSERT INTO timeline_backfills (follower_id, followee_id, created_at)
+        VALUES (NEW.follower_id, NEW.followee_id, NOW())
+        ON CONFLICT (follower_id, followee_id) DO NOTHING;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_follows_timeline
+AFTER INSERT OR DELETE OR UPDATE OF status ON follows
+FOR EACH ROW EXECUTE FUNCTION sync_timeline_follows();
+
+INSERT INTO timeline_backfills (follower_id, followee_id, created_at)
+SELECT follower_id, followee_id, NOW() FROM follows
+WHERE status = 'accepted';
+
+-- +goose Down
+DROP TRIGGER trg_follows_timeline ON follows;
+DROP FUNCTION sync_timeline_follows;
+DROP TABLE timeline_backfills;
+DROP TABLE timeline_entries;
\ No newline at end of file
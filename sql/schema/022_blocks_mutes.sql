@@ -0,0 +1,99 @@
+-- +goose Up
+CREATE TABLE blocks (
+    blocker_id UUID NOT NULL,
+    blocked_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id),
+
+    CONSTRAINT fk_blocks_blocker
+    FOREIGN KEY (blocker_id) REFERENCES users(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_blocks_blocked
+    FOREIGN KEY (blocked_id) REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_blocks_blocked_id ON blocks (blocked_id);
+
+CREATE TABLE mutes (
+    muter_id UUID NOT NULL,
+    muted_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (muter_id, muted_id),
+    CHECK (muter_id <> muted_id),
+
+    CONSTRAINT fk_mutes_muter
+    FOREIGN KEY (muter_id) REFERENCES users(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_mutes_muted
+    FOREIGN KEY (muted_id) REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+-- users_blocked reports whether either user has blocked the other. Blocks
+-- hide chirps both ways.
+-- +goose StatementBegin
+CREATE FUNCTION users_blocked(first_user_id UUID, second_user_id UUID) RETURNS BOOLEAN AS $$
+    SELECT EXISTS (
+        SELECT 1 FROM blocks
+        WHERE (blocker_id = first_user_id AND blocked_id = second_user_id)
+            OR (blocker_id = second_user_id AND blocked_id = first_user_id)
+    );
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION chirp_visible_to(chirp_author_id UUID, chirp_visibility TEXT, viewer_id UUID) RETURNS BOOLEAN AS $$
+    SELECT (viewer_id IS NULL OR NOT users_blocked(chirp_author_id, viewer_id))
+        AND (chirp_visibility = 'public'
+        OR (viewer_id IS NOT NULL AND (
+            chirp_author_id = viewer_id
+            OR (chirp_visibility = 'followers' AND EXISTS (
+                SELECT 1 FROM follows
+                WHERE follows.follower_id = viewer_id
+                    AND follows.followee_id = chirp_author_id
+                    AND follows.status = 'accepted'
+            ))
+        )));
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- chirp_listed_for is chirp_visible_to for lists of chirps such as
+-- timelines and search results, which also leave out muted authors. A muted
+-- chirp can still be opened directly.
+-- +goose StatementBegin
+CREATE FUNCTION chirp_listed_for(chirp_author_id UUID, chirp_visibility TEXT, viewer_id UUID) RETURNS BOOLEAN AS $$
+    SELECT chirp_visible_to(chirp_author_id, chirp_visibility, viewer_id)
+        AND (viewer_id IS NULL OR NOT EXISTS (
+            SELECT 1 FROM mutes
+            WHERE mutes.muter_id = viewer_id AND mutes.muted_id = chirp_author_id
+        ));
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION chirp_listed_for;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION chirp_visible_to(chirp_author_id UUID, chirp_visibility TEXT, viewer_id UUID) RETURNS BOOLEAN AS $$
+    SELECT chirp_visibility = 'public'
+        OR (viewer_id IS NOT NULL AND (
+            chirp_author_id = viewer_id
+            OR (chirp_visibility = 'followers' AND EXISTS (
+                SELECT 1 FROM follows
+                WHERE follows.follower_id = viewer_id
+                    AND follows.followee_id = chirp_author_id
+                    AND follows.status = 'accepted'
+            ))
+        ));
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+DROP FUNCTION users_blocked;
+DROP TABLE mutes;
+DROP TABLE blocks;
\ No newline at end of file
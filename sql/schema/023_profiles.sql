@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN display_name TEXT NOT NULL DEFAULT '',
+ADD COLUMN bio TEXT NOT NULL DEFAULT '',
+ADD COLUMN location TEXT NOT NULL DEFAULT '',
+ADD COLUMN website TEXT NOT NULL DEFAULT '',
+ADD COLUMN avatar_id UUID,
+ADD CONSTRAINT fk_users_avatar
+    FOREIGN KEY (avatar_id) REFERENCES media_items(id)
+    ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE users
+DROP CONSTRAINT fk_users_avatar,
+DROP COLUMN avatar_id,
+DROP COLUMN website,
+DROP COLUMN location,
+DROP COLUMN bio,
+DROP COLUMN display_name;
\ No newline at end of file
@@ -0,0 +1,80 @@
+-- +goose Up
+-- Similar events are grouped into one notification, such as every like of a
+-- chirp since the user last read their notifications. seq orders
+-- notifications by their latest event and is what clients mark as read up
+-- to.
+CREATE SEQUENCE notification_seq;
+
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY,
+    seq BIGINT NOT NULL DEFAULT nextval('notification_seq'),
+    user_id UUID NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('follow', 'follow_request', 'like', 'rechirp', 'quote', 'mention')),
+    chirp_id UUID,
+    group_key TEXT NOT NULL,
+    actor_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    read_at TIMESTAMP,
+
+    CONSTRAINT fk_notifications_users
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_notifications_chirps
+    FOREIGN KEY (chirp_id) REFERENCES chirps(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notifications_user_seq ON notifications (user_id, seq DESC);
+CREATE UNIQUE INDEX idx_notifications_unread_group ON notifications (user_id, group_key) WHERE read_at IS NULL;
+
+CREATE TABLE notification_actors (
+    notification_id UUID NOT NULL,
+    actor_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (notification_id, actor_id),
+
+    CONSTRAINT fk_notification_actors_notifications
+    FOREIGN KEY (notification_id) REFERENCES notifications(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_notification_actors_users
+    FOREIGN KEY (actor_id) REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+-- +goose StatementBegin
+CREATE FUNCTION update_notification_actor_count() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE notifications SET actor_count = actor_count + 1 WHERE id = NEW.notification_id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_notification_actors_count
+AFTER INSERT ON notification_actors
+FOR EACH ROW EXECUTE FUNCTION update_notification_actor_count();
+
+-- Every notification type is enabled unless the user has turned it off.
+CREATE TABLE notification_preferences (
+    user_id UUID NOT NULL,
+    type TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+
+    PRIMARY KEY (user_id, type),
+
+    CONSTRAINT fk_notification_preferences_users
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE notification_preferences;
+DROP TRIGGER trg_notification_actors_count ON notification_actors;
+DROP FUNCTION update_notification_actor_count;
+DROP TABLE notification_actors;
+DROP TABLE notifications;
+DROP SEQUENCE notification_seq;
\ No newline at end of file
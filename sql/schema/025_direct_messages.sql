@@ -0,0 +1,77 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN dms_from_following_only BOOLEAN NOT NULL DEFAULT false;
+
+-- direct_key identifies the one-to-one conversation between two users, so
+-- that there is only ever one of them. It is NULL for group conversations.
+CREATE TABLE conversations (
+    id UUID PRIMARY KEY,
+    direct_key TEXT UNIQUE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    last_message_at TIMESTAMP
+);
+
+-- last_read_seq is the seq of the latest message the participant has read,
+-- which is how read receipts are shown.
+CREATE TABLE conversation_participants (
+    conversation_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    joined_at TIMESTAMP NOT NULL,
+    last_read_seq BIGINT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (conversation_id, user_id),
+
+    CONSTRAINT fk_conversation_participants_conversations
+    FOREIGN KEY (conversation_id) REFERENCES conversations(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_conversation_participants_users
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_conversation_participants_user_id ON conversation_participants (user_id);
+
+CREATE SEQUENCE message_seq;
+
+-- body is encrypted by the application, the database never sees it in clear.
+CREATE TABLE messages (
+    id UUID PRIMARY KEY,
+    seq BIGINT NOT NULL DEFAULT nextval('message_seq'),
+    conversation_id UUID NOT NULL,
+    sender_id UUID NOT NULL,
+    body BYTEA NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_messages_conversations
+    FOREIGN KEY (conversation_id) REFERENCES conversations(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_messages_users
+    FOREIGN KEY (sender_id) REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_messages_conversation_seq ON messages (conversation_id, seq DESC);
+
+-- +goose StatementBegin
+CREATE FUNCTION update_conversation_last_message() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE conversations SET last_message_at = NEW.created_at, updated_at = NEW.created_at WHERE id = NEW.conversation_id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_messages_last_message
+AFTER INSERT ON messages
+FOR EACH ROW EXECUTE FUNCTION update_conversation_last_message();
+
+-- +goose Down
+DROP TRIGGER trg_messages_last_message ON messages;
+DROP FUNCTION update_conversation_last_message;
+DROP TABLE messages;
+DROP SEQUENCE message_seq;
+DROP TABLE conversation_participants;
+DROP TABLE conversations;
+ALTER TABLE users DROP COLUMN dms_from_following_only;
\ No newline at end of file
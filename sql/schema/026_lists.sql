@@ -0,0 +1,58 @@
+-- +goose Up
+CREATE TABLE lists (
+    id UUID PRIMARY KEY,
+    owner_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    is_private BOOLEAN NOT NULL DEFAULT false,
+    member_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_lists_users
+    FOREIGN KEY (owner_id) REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_lists_owner_id ON lists (owner_id, created_at DESC);
+
+CREATE TABLE list_members (
+    list_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (list_id, user_id),
+
+    CONSTRAINT fk_list_members_lists
+    FOREIGN KEY (list_id) REFERENCES lists(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_list_members_users
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_list_members_user_id ON list_members (user_id);
+
+-- +goose StatementBegin
+CREATE FUNCTION update_list_member_count() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE lists SET member_count = member_count + 1 WHERE id = NEW.list_id;
+    ELSE
+        UPDATE lists SET member_count = member_count - 1 WHERE id = OLD.list_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_list_members_count
+AFTER INSERT OR DELETE ON list_members
+FOR EACH ROW EXECUTE FUNCTION update_list_member_count();
+
+-- +goose Down
+DROP TRIGGER trg_list_members_count ON list_members;
+DROP FUNCTION update_list_member_count;
+DROP TABLE list_members;
+DROP TABLE lists;
\ No newline at end of file
@@ -0,0 +1,44 @@
+-- +goose Up
+CREATE TABLE bookmark_folders (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+
+    UNIQUE (user_id, name),
+
+    CONSTRAINT fk_bookmark_folders_users
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+-- Deleting a folder keeps its bookmarks, which become unfiled.
+CREATE TABLE bookmarks (
+    user_id UUID NOT NULL,
+    chirp_id UUID NOT NULL,
+    folder_id UUID,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (user_id, chirp_id),
+
+    CONSTRAINT fk_bookmarks_users
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_bookmarks_chirps
+    FOREIGN KEY (chirp_id) REFERENCES chirps(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_bookmarks_bookmark_folders
+    FOREIGN KEY (folder_id) REFERENCES bookmark_folders(id)
+    ON DELETE SET NULL
+);
+
+CREATE INDEX idx_bookmarks_user_created_at ON bookmarks (user_id, created_at DESC);
+CREATE INDEX idx_bookmarks_folder_created_at ON bookmarks (folder_id, created_at DESC);
+CREATE INDEX idx_bookmarks_chirp_id ON bookmarks (chirp_id);
+
+-- +goose Down
+DROP TABLE bookmarks;
+DROP TABLE bookmark_folders;
\ No newline at end of file
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE pinned_chirps (
+    user_id UUID NOT NULL,
+    chirp_id UUID NOT NULL,
+    pinned_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (user_id, chirp_id),
+
+    CONSTRAINT fk_pinned_chirps_users
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_pinned_chirps_chirps
+    FOREIGN KEY (chirp_id) REFERENCES chirps(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_pinned_chirps_chirp_id ON pinned_chirps (chirp_id);
+
+-- +goose Down
+DROP TABLE pinned_chirps;
\ No newline at end of file
@@ -0,0 +1,17 @@
+-- +goose Up
+-- fanned_out records whether a chirp was copied into its author's followers'
+-- timelines when it was posted. Chirps that were not are merged in when a
+-- timeline is read, however many followers the author has by then.
+ALTER TABLE chirps
+ADD COLUMN fanned_out BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE chirps SET fanned_out = true
+WHERE EXISTS (SELECT 1 FROM timeline_entries WHERE timeline_entries.chirp_id = chirps.id);
+
+CREATE INDEX idx_chirps_user_not_fanned_out ON chirps (user_id, created_at DESC) WHERE NOT fanned_out;
+
+-- +goose Down
+DROP INDEX idx_chirps_user_not_fanned_out;
+
+ALTER TABLE chirps
+DROP COLUMN fanned_out;
\ No newline at end of file
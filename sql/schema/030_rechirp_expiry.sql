@@ -0,0 +1,15 @@
+-- +goose Up
+-- Rechirps expire along with the chirp they share.
+UPDATE chirps SET expires_at = original.expires_at
+FROM chirps AS original
+WHERE chirps.rechirp_of_id = original.id AND original.expires_at IS NOT NULL;
+
+CREATE INDEX idx_media_items_blob_key ON media_items (blob_key);
+CREATE INDEX idx_media_items_thumbnail_key ON media_items (thumbnail_key);
+
+-- +goose Down
+DROP INDEX idx_media_items_thumbnail_key;
+DROP INDEX idx_media_items_blob_key;
+
+UPDATE chirps SET expires_at = NULL
+WHERE rechirp_of_id IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Rechirps are now removed outright when deleted. Ones deleted on their own
+-- before, rather than along with their original or their author's account,
+-- can't be restored and only keep the user from rechirping again.
+DELETE FROM chirps AS rechirp
+USING chirps AS original, users
+WHERE rechirp.rechirp_of_id = original.id AND users.id = rechirp.user_id
+AND rechirp.deleted_at IS NOT NULL
+AND rechirp.deleted_at IS DISTINCT FROM original.deleted_at
+AND users.deleted_at IS NULL;
+
+-- +goose Down
\ No newline at end of file
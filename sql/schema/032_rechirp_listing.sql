@@ -0,0 +1,18 @@
+-- +goose Up
+-- rechirp_listed_for applies chirp_listed_for to the chirp a rechirp shares,
+-- so lists leave out rechirps of authors the viewer blocked, was blocked by
+-- or muted, and of chirps they may not see. It is true for other chirps.
+-- +goose StatementBegin
+CREATE FUNCTION rechirp_listed_for(original_id UUID, viewer_id UUID) RETURNS BOOLEAN AS $$
+    SELECT original_id IS NULL OR EXISTS (
+        SELECT 1 FROM chirps AS original
+        WHERE original.id = original_id
+            AND original.deleted_at IS NULL
+            AND (original.expires_at IS NULL OR original.expires_at > NOW())
+            AND chirp_listed_for(original.user_id, original.visibility, viewer_id)
+    );
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION rechirp_listed_for;
\ No newline at end of file
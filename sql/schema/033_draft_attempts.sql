@@ -0,0 +1,12 @@
+-- +goose Up
+-- A draft that fails to publish for a reason other than its content is retried
+-- later, so it doesn't hold up the drafts due after it. It is marked as failed
+-- once it runs out of attempts.
+ALTER TABLE drafts
+ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN retry_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE drafts
+DROP COLUMN retry_at,
+DROP COLUMN attempts;
\ No newline at end of file
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+	"github.com/google/uuid"
+)
+
+// timelineBackfillSize is how many of a followee's most recent chirps are
+// copied into a new follower's timeline.
+const timelineBackfillSize = 200
+
+// encodeTimelineCursor returns an opaque cursor pointing just past the given
+// chirp, which is the last one of a page.
+func encodeTimelineCursor(last database.Chirp) string {
+	raw := last.CreatedAt.UTC().Format(time.RFC3339Nano) + "," + last.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeTimelineCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.UUID{}, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+
+	createdAtString, idString, found := strings.Cut(string(raw), ",")
+	if !found {
+		return time.Time{}, uuid.UUID{}, fmt.Errorf("invalid cursor %q", raw)
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtString)
+	if err != nil {
+		return time.Time{}, uuid.UUID{}, fmt.Errorf("invalid cursor time: %w", err)
+	}
+
+	id, err := uuid.Parse(idString)
+	if err != nil {
+		return time.Time{}, uuid.UUID{}, fmt.Errorf("invalid cursor id: %w", err)
+	}
+
+	return createdAt, id, nil
+}
+
+// backfillTimelines copies the recent chirps of newly followed accounts into
+// their followers' timelines.
+func (cfg *apiConfig) backfillTimelines(ctx context.Context) error {
+	for ctx.Err() == nil {
+		backfilled, err := cfg.backfillNextTimeline(ctx)
+		if err != nil {
+			return err
+		}
+		if !backfilled {
+			return nil
+		}
+	}
+	return nil
+}
+
+// backfillNextTimeline claims one queued follow with FOR UPDATE SKIP LOCKED,
+// so replicas share the queue, and reports whether there was one.
+func (cfg *apiConfig) backfillNextTimeline(ctx context.Context) (bool, error) {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	backfill, err := qtx.ClaimTimelineBackfill(ctx)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim timeline backfill: %w", err)
+	}
+
+	err = qtx.BackfillTimeline(ctx, database.BackfillTimelineParams{
+		FollowerID: backfill.FollowerID,
+		FolloweeID: backfill.FolloweeID,
+		Limit:      timelineBackfillSize,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to backfill timeline: %w", err)
+	}
+
+	err = qtx.DeleteTimelineBackfill(ctx, database.DeleteTimelineBackfillParams{
+		FollowerID: backfill.FollowerID,
+		FolloweeID: backfill.FolloweeID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete timeline backfill: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, fmt.Errorf("failed to commit timeline backfill: %w", err)
+	}
+	return true, nil
+}
+
+func (cfg *apiConfig) handlerGetTimeline(w http.ResponseWriter, req *http.Request) {
+	userID, ok := cfg.authenticate(w, req)
+	if !ok {
+		return
+	}
+
+	limit, err := parseLimit(req)
+	if err != nil {
+		log.Printf("failed to parse pagination: %s", err)
+		respondWithError(w, 400, "Invalid pagination")
+		return
+	}
+
+	params := database.GetTimelineParams{
+		UserID: userID,
+		Limit:  limit,
+	}
+	if cursor := req.URL.Query().Get("cursor"); cursor != "" {
+		createdAt, id, err := decodeTimelineCursor(cursor)
+		if err != nil {
+			log.Printf("failed to decode cursor: %s", err)
+			respondWithError(w, 400, "Invalid cursor")
+			return
+		}
+		params.BeforeCreatedAt = sql.NullTime{Time: createdAt, Valid: true}
+		params.BeforeID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	// Each source (the timeline entries, the user's own chirps and the chirps
+	// of followed accounts that are not fanned out) is read in cursor order up
+	// to one page, and only the merged page is joined with the chirps.
+	chirps, err := cfg.dbQueries.GetTimeline(req.Context(), params)
+	if err != nil {
+		log.Printf("failed to get timeline: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	chirpsBody, err := cfg.buildChirps(req.Context(), chirps, uuid.NullUUID{UUID: userID, Valid: true})
+	if err != nil {
+		log.Printf("failed to build chirps response: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := struct {
+		Chirps     []chirp `json:"chirps"`
+		NextCursor string  `json:"next_cursor,omitempty"`
+	}{
+		Chirps: chirpsBody,
+	}
+	if len(chirps) == int(limit) {
+		respBody.NextCursor = encodeTimelineCursor(chirps[len(chirps)-1])
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/LouisRemes-95/chirpy.git/internal/database"
+)
+
+const trendingSize = 20
+
+type trendingWindow struct {
+	name   string
+	length time.Duration
+}
+
+// Scores decay with a half-life of a quarter of the window, so recent
+// activity counts more than activity at the start of the window.
+var trendingWindows = []trendingWindow{
+	{name: "1h", length: time.Hour},
+	{name: "24h", length: 24 * time.Hour},
+	{name: "7d", length: 7 * 24 * time.Hour},
+}
+
+type trendingHashtag struct {
+	Tag   string  `json:"tag"`
+	Score float64 `json:"score"`
+}
+
+// refreshTrending recomputes the materialized trending tables for every
+// window. Each window is replaced atomically so readers never see it empty.
+func (cfg *apiConfig) refreshTrending(ctx context.Context) error {
+	for _, window := range trendingWindows {
+		err := cfg.refreshTrendingWindow(ctx, window)
+		if err != nil {
+			return fmt.Errorf("failed to refresh trending for %s: %w", window.name, err)
+		}
+	}
+	return nil
+}
+
+func (cfg *apiConfig) refreshTrendingWindow(ctx context.Context, window trendingWindow) error {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	err = qtx.DeleteTrendingHashtags(ctx, window.name)
+	if err != nil {
+		return fmt.Errorf("failed to delete trending hashtags: %w", err)
+	}
+
+	err = qtx.ComputeTrendingHashtags(ctx, database.ComputeTrendingHashtagsParams{
+		Period:          window.name,
+		HalfLifeSeconds: (window.length / 4).Seconds(),
+		WindowSeconds:   window.length.Seconds(),
+		MaxResults:      trendingSize,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to compute trending hashtags: %w", err)
+	}
+
+	err = qtx.DeleteTrendingChirps(ctx, window.name)
+	if err != nil {
+		return fmt.Errorf("failed to delete trending chirps: %w", err)
+	}
+
+	err = qtx.ComputeTrendingChirps(ctx, database.ComputeTrendingChirpsParams{
+		Period:          window.name,
+		HalfLifeSeconds: (window.length / 4).Seconds(),
+		WindowSeconds:   window.length.Seconds(),
+		MaxResults:      trendingSize,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to compute trending chirps: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (cfg *apiConfig) handlerGetTrending(w http.ResponseWriter, req *http.Request) {
+	windowName := req.URL.Query().Get("window")
+	if windowName == "" {
+		windowName = "24h"
+	}
+
+	found := false
+	for _, window := range trendingWindows {
+		if window.name == windowName {
+			found = true
+		}
+	}
+	if !found {
+		respondWithError(w, 400, "Invalid window")
+		return
+	}
+
+	hashtags, err := cfg.dbQueries.GetTrendingHashtags(req.Context(), database.GetTrendingHashtagsParams{
+		Period: windowName,
+		Limit:  trendingSize,
+	})
+	if err != nil {
+		log.Printf("failed to get trending hashtags: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	viewerID := cfg.viewerID(req)
+	chirps, err := cfg.dbQueries.GetTrendingChirps(req.Context(), database.GetTrendingChirpsParams{
+		Period:   windowName,
+		ViewerID: viewerID,
+		Limit:    trendingSize,
+	})
+	if err != nil {
+		log.Printf("failed to get trending chirps: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	chirpsBody, err := cfg.buildChirps(req.Context(), chirps, viewerID)
+	if err != nil {
+		log.Printf("failed to build chirps response: %s", err)
+		respondWithError(w, 500, "Internal server error")
+		return
+	}
+
+	respBody := struct {
+		Window     string            `json:"window"`
+		ComputedAt *time.Time        `json:"computed_at,omitempty"`
+		Hashtags   []trendingHashtag `json:"hashtags"`
+		Chirps     []chirp           `json:"chirps"`
+	}{
+		Window:   windowName,
+		Hashtags: make([]trendingHashtag, len(hashtags)),
+		Chirps:   chirpsBody,
+	}
+	for i, hashtag := range hashtags {
+		respBody.Hashtags[i] = trendingHashtag{
+			Tag:   hashtag.Tag,
+			Score: hashtag.Score,
+		}
+		respBody.ComputedAt = &hashtag.ComputedAt
+	}
+
+	respondWithJSON(w, 200, respBody)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// runPeriodically runs task immediately and then every interval until ctx is
+// cancelled. Failures are logged and retried on the next tick.
+func runPeriodically(ctx context.Context, name string, interval time.Duration, task func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := task(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("%s worker failed: %s", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("%s worker stopped", name)
+			return
+		case <-ticker.C:
+		}
+	}
+}